@@ -0,0 +1,260 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"groupie-tracker/internal/textindex"
+)
+
+const (
+	IndexName     = "name"
+	IndexMember   = "member"
+	IndexLocation = "location"
+	IndexCreated  = "created"
+	IndexAlbum    = "album"
+	IndexDate     = "date"
+)
+
+type Catalog struct {
+	Data *APIData
+
+	upstream     *APIData
+	artists      []Artist
+	concerts     []Concert
+	byID         map[int]*Artist
+	relations    map[int]*Relation
+	byArtist     map[int][]Concert
+	byLocation   map[string][]int
+	byMember     map[string][]int
+	byCreation   map[int][]int
+	byFirstAlbum map[int][]int
+	locations    []string
+	anomalies    []Anomaly
+	sections     []SectionStatus
+	stalePatches []StalePatch
+	index        *textindex.Index
+}
+
+func NewCatalog(raw *APIData) *Catalog {
+	return buildCatalog(raw, raw)
+}
+
+func buildCatalog(upstream, raw *APIData) *Catalog {
+	data, mismatches := Reconcile(raw)
+
+	c := &Catalog{
+		Data:         data,
+		upstream:     upstream,
+		artists:      make([]Artist, len(data.Artists)),
+		byID:         make(map[int]*Artist, len(data.Artists)),
+		relations:    make(map[int]*Relation, len(data.Relations.Index)),
+		byArtist:     make(map[int][]Concert),
+		byLocation:   make(map[string][]int),
+		byMember:     make(map[string][]int),
+		byCreation:   make(map[int][]int),
+		byFirstAlbum: make(map[int][]int),
+	}
+
+	recorder := &anomalyRecorder{anomalies: mismatches}
+
+	copy(c.artists, data.Artists)
+	for i := range c.artists {
+		artist := &c.artists[i]
+		c.byID[artist.ID] = artist
+		c.byCreation[artist.CreationDate] = append(c.byCreation[artist.CreationDate], artist.ID)
+
+		year := 0
+		if date, ok := recorder.parseDate(artist, artist.ID, "firstAlbum", artist.FirstAlbum); ok {
+			artist.FirstAlbumDate = date
+			year = date.Year()
+		}
+		c.byFirstAlbum[year] = append(c.byFirstAlbum[year], artist.ID)
+
+		for _, member := range artist.Members {
+			key := normalizeKey(member)
+			c.byMember[key] = append(c.byMember[key], artist.ID)
+		}
+	}
+
+	for i := range data.Relations.Index {
+		relation := &data.Relations.Index[i]
+		c.relations[relation.ID] = relation
+		artist := c.byID[relation.ID]
+
+		for slug, dates := range relation.DatesLocations {
+			key := normalizeKey(slug)
+			c.byLocation[key] = append(c.byLocation[key], relation.ID)
+
+			location := ParsePlace(slug)
+			for _, dateStr := range dates {
+				date, ok := recorder.parseDate(artist, relation.ID, "datesLocations", dateStr)
+				if !ok || artist == nil {
+					continue
+				}
+				c.byArtist[relation.ID] = append(c.byArtist[relation.ID], Concert{
+					Artist:   artist,
+					Location: location,
+					Date:     date,
+				})
+			}
+		}
+	}
+
+	for _, entry := range data.Dates.Index {
+		for _, dateStr := range entry.Dates {
+			recorder.parseDate(c.byID[entry.ID], entry.ID, "dates", dateStr)
+		}
+	}
+	c.anomalies = recorder.anomalies
+
+	for id, concerts := range c.byArtist {
+		sortConcerts(concerts)
+		c.byArtist[id] = concerts
+		c.concerts = append(c.concerts, concerts...)
+	}
+	sortConcerts(c.concerts)
+
+	c.locations = make([]string, 0, len(c.byLocation))
+	for location := range c.byLocation {
+		c.locations = append(c.locations, location)
+	}
+	sort.Strings(c.locations)
+
+	c.index = buildIndex(c)
+	return c
+}
+
+func buildIndex(c *Catalog) *textindex.Index {
+	index := textindex.New()
+	for _, artist := range c.artists {
+		index.Add(IndexName, artist.Name, artist.ID)
+		for _, member := range artist.Members {
+			index.Add(IndexMember, member, artist.ID)
+		}
+		index.Add(IndexCreated, strconv.Itoa(artist.CreationDate), artist.ID)
+		index.Add(IndexAlbum, artist.FirstAlbum, artist.ID)
+
+		for _, concert := range c.byArtist[artist.ID] {
+			index.Add(IndexLocation, concert.Location.String(), artist.ID, concert.Location.SearchText())
+			index.Add(IndexDate, concert.Date.Format("02-01-2006"), artist.ID)
+		}
+	}
+	return index
+}
+
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func (c *Catalog) Artists() []Artist {
+	return c.artists
+}
+
+func (c *Catalog) Artist(id int) (*Artist, bool) {
+	artist, ok := c.byID[id]
+	return artist, ok
+}
+
+func (c *Catalog) Relation(id int) (*Relation, bool) {
+	relation, ok := c.relations[id]
+	return relation, ok
+}
+
+func (c *Catalog) Index() *textindex.Index {
+	return c.index
+}
+
+func (c *Catalog) Concerts() []Concert {
+	return c.concerts
+}
+
+func (c *Catalog) ConcertsByArtist(id int) []Concert {
+	return c.byArtist[id]
+}
+
+func (c *Catalog) Tour(id int) []PlaceConcerts {
+	return GroupByPlace(c.byArtist[id])
+}
+
+func (c *Catalog) Sections() []SectionStatus {
+	return c.sections
+}
+
+func (c *Catalog) Section(name string) *SectionStatus {
+	for i := range c.sections {
+		if c.sections[i].Name == name {
+			return &c.sections[i]
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) Degraded() bool {
+	for _, section := range c.sections {
+		if section.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Catalog) StalePatches() []StalePatch {
+	return c.stalePatches
+}
+
+func (c *Catalog) Anomalies() []Anomaly {
+	return c.anomalies
+}
+
+func (c *Catalog) Locations() []string {
+	return c.locations
+}
+
+func (c *Catalog) ArtistsWithMember(name string) []int {
+	return c.byMember[normalizeKey(name)]
+}
+
+func (c *Catalog) ArtistsAtLocation(location string) []int {
+	return c.byLocation[normalizeKey(location)]
+}
+
+func (c *Catalog) PlayedAt(queries []string) map[int]bool {
+	ids := make(map[int]bool)
+	for _, query := range queries {
+		if artists, ok := c.byLocation[normalizeKey(query)]; ok {
+			for _, id := range artists {
+				ids[id] = true
+			}
+			continue
+		}
+		for _, entry := range c.index.Search(query, IndexLocation) {
+			for _, id := range c.index.Entry(entry).IDs {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+func (c *Catalog) CreatedBetween(min, max int) map[int]bool {
+	return yearRange(c.byCreation, min, max)
+}
+
+func (c *Catalog) FirstAlbumBetween(min, max int) map[int]bool {
+	return yearRange(c.byFirstAlbum, min, max)
+}
+
+func yearRange(index map[int][]int, min, max int) map[int]bool {
+	ids := make(map[int]bool)
+	for year, artists := range index {
+		if year < min || year > max {
+			continue
+		}
+		for _, id := range artists {
+			ids[id] = true
+		}
+	}
+	return ids
+}
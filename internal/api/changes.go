@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	ChangeArtistAdded    = "artist added"
+	ChangeArtistRemoved  = "artist removed"
+	ChangeMemberAdded    = "member added"
+	ChangeMemberRemoved  = "member removed"
+	ChangeConcertAdded   = "concert added"
+	ChangeConcertRemoved = "concert removed"
+
+	defaultMaxChanges = 1000
+	changesFile       = "changes.json"
+)
+
+type Change struct {
+	Seq      int       `json:"seq"`
+	At       time.Time `json:"at"`
+	Kind     string    `json:"kind"`
+	ArtistID int       `json:"artistId"`
+	Artist   string    `json:"artist"`
+	Detail   string    `json:"detail"`
+	Location string    `json:"location,omitempty"`
+	Date     string    `json:"date,omitempty"`
+}
+
+func Diff(prev, next *Catalog) []Change {
+	var changes []Change
+
+	for _, artist := range next.Artists() {
+		old, ok := prev.Artist(artist.ID)
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeArtistAdded, ArtistID: artist.ID, Artist: artist.Name, Detail: artist.Name})
+			continue
+		}
+		for _, member := range missing(artist.Members, old.Members) {
+			changes = append(changes, Change{Kind: ChangeMemberAdded, ArtistID: artist.ID, Artist: artist.Name, Detail: member})
+		}
+		for _, member := range missing(old.Members, artist.Members) {
+			changes = append(changes, Change{Kind: ChangeMemberRemoved, ArtistID: artist.ID, Artist: artist.Name, Detail: member})
+		}
+		changes = append(changes, diffConcerts(artist, prev.ConcertsByArtist(artist.ID), next.ConcertsByArtist(artist.ID))...)
+	}
+
+	for _, artist := range prev.Artists() {
+		if _, ok := next.Artist(artist.ID); !ok {
+			changes = append(changes, Change{Kind: ChangeArtistRemoved, ArtistID: artist.ID, Artist: artist.Name, Detail: artist.Name})
+		}
+	}
+
+	return changes
+}
+
+func missing(from, in []string) []string {
+	present := make(map[string]bool, len(in))
+	for _, s := range in {
+		present[s] = true
+	}
+	var result []string
+	for _, s := range from {
+		if !present[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func diffConcerts(artist Artist, before, after []Concert) []Change {
+	key := func(c Concert) string {
+		return c.Location.Slug + "@" + c.Date.Format("2006-01-02")
+	}
+	old := make(map[string]bool, len(before))
+	for _, c := range before {
+		old[key(c)] = true
+	}
+	current := make(map[string]bool, len(after))
+	for _, c := range after {
+		current[key(c)] = true
+	}
+
+	concertChange := func(kind string, c Concert) Change {
+		return Change{
+			Kind:     kind,
+			ArtistID: artist.ID,
+			Artist:   artist.Name,
+			Detail:   c.Location.String() + " on " + c.Date.Format("2 January 2006"),
+			Location: c.Location.Slug,
+			Date:     c.Date.Format("2006-01-02"),
+		}
+	}
+
+	var changes []Change
+	for _, c := range after {
+		if !old[key(c)] {
+			changes = append(changes, concertChange(ChangeConcertAdded, c))
+		}
+	}
+	for _, c := range before {
+		if !current[key(c)] {
+			changes = append(changes, concertChange(ChangeConcertRemoved, c))
+		}
+	}
+	return changes
+}
+
+type ChangeLog struct {
+	Max  int
+	Path string
+
+	mu      sync.RWMutex
+	changes []Change
+	lastSeq int
+}
+
+func NewChangeLog() *ChangeLog {
+	return &ChangeLog{Max: defaultMaxChanges}
+}
+
+func OpenChangeLog(dir string) (*ChangeLog, error) {
+	cl := NewChangeLog()
+	cl.Path = filepath.Join(dir, changesFile)
+
+	f, err := os.Open(cl.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cl, nil
+		}
+		return cl, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&cl.changes); err != nil {
+		return cl, err
+	}
+	sort.Slice(cl.changes, func(i, j int) bool {
+		return cl.changes[i].Seq < cl.changes[j].Seq
+	})
+	if n := len(cl.changes); n > 0 {
+		cl.lastSeq = cl.changes[n-1].Seq
+	}
+	return cl, nil
+}
+
+func (l *ChangeLog) Append(at time.Time, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	for _, change := range changes {
+		l.lastSeq++
+		change.Seq = l.lastSeq
+		change.At = at.UTC()
+		l.changes = append(l.changes, change)
+	}
+	if l.Max > 0 && len(l.changes) > l.Max {
+		l.changes = append([]Change(nil), l.changes[len(l.changes)-l.Max:]...)
+	}
+	snapshot := append([]Change(nil), l.changes...)
+	l.mu.Unlock()
+
+	if l.Path == "" {
+		return nil
+	}
+	return writeJSONAtomic(l.Path, snapshot)
+}
+
+func (l *ChangeLog) Since(seq int) ([]Change, int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	i := sort.Search(len(l.changes), func(i int) bool {
+		return l.changes[i].Seq > seq
+	})
+	return append([]Change(nil), l.changes[i:]...), l.lastSeq
+}
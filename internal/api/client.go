@@ -1,130 +1,420 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
 	"sync"
 	"time"
+
+	"groupie-tracker/internal/httpclient"
 )
 
 const (
-	baseURL      = "https://groupietrackers.herokuapp.com/api"
-	artistsURL   = baseURL + "/artists"
-	locationsURL = baseURL + "/locations"
-	datesURL     = baseURL + "/dates"
-	relationsURL = baseURL + "/relation"
-	cacheTimeout = 30 * time.Minute
+	defaultRefreshInterval = 30 * time.Minute
+	defaultRetryMin        = 5 * time.Second
+	defaultRetryMax        = 5 * time.Minute
+	defaultFetchTimeout    = 30 * time.Second
 )
 
-var (
-	cache      *APIData
-	cacheMutex sync.RWMutex
-	lastFetch  time.Time
-)
+type Client struct {
+	RefreshInterval time.Duration
+	RetryMin        time.Duration
+	RetryMax        time.Duration
+	FetchTimeout    time.Duration
+
+	source    DataSource
+	snapshots *SnapshotStore
+	changes   *ChangeLog
+	overrides *Overrides
+	imports   *Imports
+
+	cacheMutex  sync.RWMutex
+	cache       *Catalog
+	lastFetch   time.Time
+	lastAttempt time.Time
+	lastErr     error
+	failures    int
+
+	fetchMutex sync.Mutex
+	inflight   *fetchCall
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+type fetchCall struct {
+	done    chan struct{}
+	catalog *Catalog
+	err     error
+}
+
+type CacheStatus struct {
+	Loaded      bool      `json:"loaded"`
+	LastFetch   time.Time `json:"lastFetch"`
+	LastAttempt time.Time `json:"lastAttempt"`
+	Age         string    `json:"age"`
+	Stale       bool      `json:"stale"`
+	LastError   string    `json:"lastError,omitempty"`
+	Failures    int       `json:"failures"`
+
+	Sections []SectionStatus `json:"sections,omitempty"`
+}
+
+func NewClient(source DataSource) *Client {
+	return &Client{
+		RefreshInterval: defaultRefreshInterval,
+		RetryMin:        defaultRetryMin,
+		RetryMax:        defaultRetryMax,
+		FetchTimeout:    defaultFetchTimeout,
+		source:          source,
+		changes:         NewChangeLog(),
+	}
+}
 
-func FetchAPI() (*APIData, error) {
-	cacheMutex.RLock()
-	if cache != nil && time.Since(lastFetch) < cacheTimeout {
-		cacheMutex.RUnlock()
-		return cache, nil
+func (c *Client) UseSnapshots(store *SnapshotStore) error {
+	c.snapshots = store
+
+	changes, err := OpenChangeLog(store.Dir)
+	if err != nil {
+		log.Println("Failed to load change log:", err)
 	}
-	cacheMutex.RUnlock()
+	c.changes = changes
 
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
+	snapshot, err := store.Latest()
+	if err != nil {
+		return err
+	}
+	if seeder, ok := c.source.(interface{ Seed(*APIData) }); ok {
+		seeder.Seed(snapshot.Data)
+	}
 
-	if cache != nil && time.Since(lastFetch) < cacheTimeout {
-		return cache, nil
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+	if c.cache == nil {
+		c.cache = c.newCatalog(snapshot.Data)
+		c.cache.sections = mergeSections(snapshot.Data, snapshot.FetchedAt, nil, nil)
+		c.lastFetch = snapshot.FetchedAt
 	}
+	return nil
+}
 
-	data := &APIData{}
+func (c *Client) UseOverrides(overrides *Overrides) {
+	c.overrides = overrides
+}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, 4)
+func (c *Client) UseImports(imports *Imports) {
+	c.imports = imports
+}
 
-	wg.Add(4)
+func (c *Client) newCatalog(data *APIData) *Catalog {
+	patched, stale := c.overrides.Apply(c.imports.Merge(data))
+	for _, patch := range stale {
+		log.Printf("Override for artist %d %s no longer matches: %s", patch.Patch.ArtistID, patch.Patch.Field, patch.Reason)
+	}
 
-	go func() {
-		defer wg.Done()
-		if err := fetchJSON(artistsURL, &data.Artists); err != nil {
-			errChan <- fmt.Errorf("artists: %w", err)
+	catalog := buildCatalog(data, patched)
+	catalog.stalePatches = stale
+	return catalog
+}
+
+func (c *Client) FetchAPI() (*APIData, error) {
+	return c.FetchAPIContext(context.Background())
+}
+
+func (c *Client) FetchAPIContext(ctx context.Context) (*APIData, error) {
+	catalog, err := c.CatalogContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Data, nil
+}
+
+func (c *Client) Catalog() (*Catalog, error) {
+	return c.CatalogContext(context.Background())
+}
+
+func (c *Client) CatalogContext(ctx context.Context) (*Catalog, error) {
+	for {
+		c.cacheMutex.RLock()
+		catalog := c.cache
+		c.cacheMutex.RUnlock()
+		if catalog != nil {
+			return catalog, nil
 		}
-	}()
 
-	go func() {
-		defer wg.Done()
-		if err := fetchJSON(locationsURL, &data.Locations); err != nil {
-			errChan <- fmt.Errorf("locations: %w", err)
+		call, leader := c.joinFetch(ctx)
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
-	}()
 
-	go func() {
-		defer wg.Done()
-		if err := fetchJSON(datesURL, &data.Dates); err != nil {
-			errChan <- fmt.Errorf("dates: %w", err)
+		if call.err == nil {
+			return call.catalog, nil
 		}
-	}()
+		if !leader && errors.Is(call.err, context.Canceled) {
+			continue
+		}
+		return nil, call.err
+	}
+}
+
+func (c *Client) Refresh() error {
+	return c.RefreshContext(context.Background())
+}
+
+func (c *Client) RefreshContext(ctx context.Context) error {
+	call, _ := c.joinFetch(ctx)
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) joinFetch(ctx context.Context) (*fetchCall, bool) {
+	c.fetchMutex.Lock()
+	if c.inflight != nil {
+		call := c.inflight
+		c.fetchMutex.Unlock()
+		return call, false
+	}
+	call := &fetchCall{done: make(chan struct{})}
+	c.inflight = call
+	c.fetchMutex.Unlock()
 
 	go func() {
-		defer wg.Done()
-		if err := fetchJSON(relationsURL, &data.Relations); err != nil {
-			errChan <- fmt.Errorf("relations: %w", err)
-		}
+		call.catalog, call.err = c.fetch(ctx)
+
+		c.fetchMutex.Lock()
+		c.inflight = nil
+		c.fetchMutex.Unlock()
+		close(call.done)
 	}()
+	return call, true
+}
+
+func (c *Client) fetch(ctx context.Context) (*Catalog, error) {
+	if c.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.FetchTimeout)
+		defer cancel()
+	}
 
-	wg.Wait()
-	close(errChan)
+	data, err := c.source.Fetch(ctx)
+	now := time.Now()
 
-	for err := range errChan {
+	if data == nil && errors.Is(err, context.Canceled) {
 		return nil, err
 	}
 
-	cache = data
-	lastFetch = time.Now()
-	return data, nil
-}
+	c.cacheMutex.RLock()
+	previous := c.cache
+	c.cacheMutex.RUnlock()
 
-func fetchJSON(url string, target interface{}) error {
-	resp, err := http.Get(url)
+	var catalog *Catalog
+	var sectionErr *SectionError
+	partial := data != nil && errors.As(err, &sectionErr)
+	if err == nil || partial {
+		var failed map[string]error
+		if partial {
+			failed = sectionErr.Errors
+		}
+		if previous != nil || failed[artistsEndpoint] == nil {
+			sections := mergeSections(data, now, failed, previous)
+			catalog = c.newCatalog(data)
+			catalog.sections = sections
+		}
+	}
+
+	c.cacheMutex.Lock()
+	c.lastAttempt = now
+	c.lastErr = err
 	if err != nil {
-		return err
+		c.failures++
+	} else {
+		c.failures = 0
+	}
+	if catalog == nil {
+		c.cacheMutex.Unlock()
+		return nil, err
+	}
+
+	c.cache = catalog
+	c.lastFetch = now
+	c.cacheMutex.Unlock()
+
+	if err != nil {
+		log.Printf("Partial refresh, serving last known data for failed sections: %v", err)
+	}
+
+	if previous != nil {
+		if diff := Diff(previous, catalog); len(diff) > 0 {
+			log.Printf("Upstream data changed: %d change(s)", len(diff))
+			if err := c.changes.Append(now, diff); err != nil {
+				log.Println("Failed to save change log:", err)
+			}
+		}
+	}
+
+	if c.snapshots != nil {
+		if err := c.snapshots.Save(data, now); err != nil {
+			log.Println("Failed to save snapshot:", err)
+		}
 	}
-	defer resp.Body.Close()
+	return catalog, nil
+}
+
+func (c *Client) Changes(since int) ([]Change, int) {
+	return c.changes.Since(since)
+}
+
+func (c *Client) Degraded() bool {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	if c.cache == nil {
+		return false
 	}
+	if c.lastErr != nil || c.cache.Degraded() {
+		return true
+	}
+	if reporter, ok := c.source.(interface{ Degraded() bool }); ok {
+		return reporter.Degraded()
+	}
+	return false
+}
+
+func (c *Client) Status() CacheStatus {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
 
-	return json.NewDecoder(resp.Body).Decode(target)
+	status := CacheStatus{
+		Loaded:      c.cache != nil,
+		LastFetch:   c.lastFetch,
+		LastAttempt: c.lastAttempt,
+		Failures:    c.failures,
+	}
+	if c.cache != nil {
+		age := time.Since(c.lastFetch)
+		status.Age = age.Round(time.Second).String()
+		status.Stale = age > c.RefreshInterval
+		status.Sections = c.cache.Sections()
+	}
+	if c.lastErr != nil {
+		status.LastError = c.lastErr.Error()
+	}
+	return status
 }
 
-func GetArtistByID(id int) (*Artist, error) {
-	data, err := FetchAPI()
+func (c *Client) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stop = cancel
+	c.done = make(chan struct{})
+	go c.refreshLoop(ctx)
+}
+
+func (c *Client) Stop() {
+	if c.stop == nil {
+		return
+	}
+	c.stop()
+	<-c.done
+	c.stop = nil
+}
+
+func (c *Client) refreshLoop(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		timer := time.NewTimer(c.nextRefresh())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := c.RefreshContext(ctx); err != nil && ctx.Err() == nil {
+			status := c.Status()
+			log.Printf("Refresh failed (%d in a row, serving data from %s ago): %v", status.Failures, status.Age, err)
+		}
+	}
+}
+
+func (c *Client) nextRefresh() time.Duration {
+	c.cacheMutex.RLock()
+	defer c.cacheMutex.RUnlock()
+
+	if c.failures > 0 {
+		return httpclient.Backoff(c.RetryMin, c.RetryMax, c.failures)
+	}
+	if c.cache == nil {
+		return 0
+	}
+	wait := c.RefreshInterval - time.Since(c.lastFetch)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+func (c *Client) UpstreamStatus() []httpclient.BreakerStatus {
+	if reporter, ok := c.source.(interface {
+		Breakers() []httpclient.BreakerStatus
+	}); ok {
+		return reporter.Breakers()
+	}
+	return nil
+}
+
+func (c *Client) SchemaDrift() []DriftWarning {
+	if reporter, ok := c.source.(interface{ SchemaDrift() []DriftWarning }); ok {
+		return reporter.SchemaDrift()
+	}
+	return nil
+}
+
+func (c *Client) SourceHealth() []UpstreamHealth {
+	if reporter, ok := c.source.(interface{ Health() []UpstreamHealth }); ok {
+		return reporter.Health()
+	}
+	return nil
+}
+
+func (c *Client) GetArtistByID(id int) (*Artist, error) {
+	return c.GetArtistByIDContext(context.Background(), id)
+}
+
+func (c *Client) GetArtistByIDContext(ctx context.Context, id int) (*Artist, error) {
+	catalog, err := c.CatalogContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, artist := range data.Artists {
-		if artist.ID == id {
-			return &artist, nil
-		}
+	artist, ok := catalog.Artist(id)
+	if !ok {
+		return nil, fmt.Errorf("artist not found")
 	}
-	return nil, fmt.Errorf("artist not found")
+	return artist, nil
 }
 
-func GetRelationByID(id int) (*Relation, error) {
-	data, err := FetchAPI()
+func (c *Client) GetRelationByID(id int) (*Relation, error) {
+	return c.GetRelationByIDContext(context.Background(), id)
+}
+
+func (c *Client) GetRelationByIDContext(ctx context.Context, id int) (*Relation, error) {
+	catalog, err := c.CatalogContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, rel := range data.Relations.Index {
-		if rel.ID == id {
-			return &rel, nil
-		}
+	relation, ok := catalog.Relation(id)
+	if !ok {
+		return nil, fmt.Errorf("relation not found")
 	}
-	return nil, fmt.Errorf("relation not found")
+	return relation, nil
 }
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const twoDigitYearPivot = 30
+
+const (
+	QuirkWhitespace     = "surrounding whitespace"
+	QuirkAsterisk       = "leading asterisk"
+	QuirkSeparator      = "non-standard separator"
+	QuirkUnpadded       = "unpadded day or month"
+	QuirkTwoDigitYear   = "two-digit year"
+	QuirkYearFirstOrder = "year-first order"
+)
+
+type Anomaly struct {
+	ArtistID int    `json:"artistId"`
+	Artist   string `json:"artist"`
+	Field    string `json:"field"`
+	Value    string `json:"value"`
+	Problem  string `json:"problem"`
+	Repaired bool   `json:"repaired"`
+}
+
+func ParseDate(s string) (time.Time, error) {
+	t, _, err := ParseDateTolerant(s)
+	return t, err
+}
+
+func ParseDateTolerant(s string) (time.Time, []string, error) {
+	var quirks []string
+
+	value := strings.TrimSpace(s)
+	if value != s {
+		quirks = append(quirks, QuirkWhitespace)
+	}
+	if strings.HasPrefix(value, "*") {
+		value = strings.TrimLeft(value, "*")
+		quirks = append(quirks, QuirkAsterisk)
+	}
+	if value == "" {
+		return time.Time{}, quirks, fmt.Errorf("empty date")
+	}
+
+	if strings.ContainsAny(value, "/.") {
+		value = strings.NewReplacer("/", "-", ".", "-").Replace(value)
+		quirks = append(quirks, QuirkSeparator)
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 3 {
+		return time.Time{}, quirks, fmt.Errorf("invalid date %q", s)
+	}
+	if len(parts[0]) == 4 {
+		parts[0], parts[2] = parts[2], parts[0]
+		quirks = append(quirks, QuirkYearFirstOrder)
+	}
+
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return time.Time{}, quirks, fmt.Errorf("invalid date %q", s)
+		}
+		nums[i] = n
+	}
+	day, month, year := nums[0], nums[1], nums[2]
+
+	if len(parts[0]) == 1 || len(parts[1]) == 1 {
+		quirks = append(quirks, QuirkUnpadded)
+	}
+	switch len(parts[2]) {
+	case 2:
+		year += 1900
+		if nums[2] < twoDigitYearPivot {
+			year += 100
+		}
+		quirks = append(quirks, QuirkTwoDigitYear)
+	case 4:
+	default:
+		return time.Time{}, quirks, fmt.Errorf("invalid year in %q", s)
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, quirks, fmt.Errorf("invalid date %q", s)
+	}
+	return t, quirks, nil
+}
+
+type anomalyRecorder struct {
+	anomalies []Anomaly
+}
+
+func (r *anomalyRecorder) parseDate(artist *Artist, artistID int, field, value string) (time.Time, bool) {
+	t, quirks, err := ParseDateTolerant(value)
+
+	name := ""
+	if artist != nil {
+		name = artist.Name
+	}
+	for _, quirk := range quirks {
+		r.anomalies = append(r.anomalies, Anomaly{
+			ArtistID: artistID,
+			Artist:   name,
+			Field:    field,
+			Value:    value,
+			Problem:  quirk,
+			Repaired: err == nil,
+		})
+	}
+	if err != nil {
+		r.anomalies = append(r.anomalies, Anomaly{
+			ArtistID: artistID,
+			Artist:   name,
+			Field:    field,
+			Value:    value,
+			Problem:  err.Error(),
+		})
+		return time.Time{}, false
+	}
+	return t, true
+}
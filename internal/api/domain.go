@@ -0,0 +1,105 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"groupie-tracker/internal/textnorm"
+)
+
+var upperCaseWords = map[string]bool{
+	"usa": true,
+	"uk":  true,
+}
+
+type Place struct {
+	Slug    string
+	City    string
+	Region  string
+	Country string
+}
+
+type Concert struct {
+	Artist   *Artist
+	Location Place
+	Date     time.Time
+}
+
+type PlaceConcerts struct {
+	Location Place
+	Dates    []time.Time
+}
+
+func ParsePlace(slug string) Place {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(slug)), "-")
+	for i, part := range parts {
+		parts[i] = formatPlaceName(part)
+	}
+
+	loc := Place{Slug: slug}
+	switch len(parts) {
+	case 1:
+		loc.City = parts[0]
+	case 2:
+		loc.City, loc.Country = parts[0], parts[1]
+	default:
+		loc.City = parts[0]
+		loc.Region = strings.Join(parts[1:len(parts)-1], ", ")
+		loc.Country = parts[len(parts)-1]
+	}
+	return loc
+}
+
+func formatPlaceName(part string) string {
+	if upperCaseWords[part] {
+		return strings.ToUpper(part)
+	}
+	words := strings.Fields(strings.ReplaceAll(part, "_", " "))
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToTitle(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func (l Place) String() string {
+	var parts []string
+	for _, part := range []string{l.City, l.Region, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (l Place) SearchText() string {
+	return textnorm.Fold(l.Slug + " " + l.String())
+}
+
+func GroupByPlace(concerts []Concert) []PlaceConcerts {
+	var groups []PlaceConcerts
+	index := make(map[string]int)
+
+	for _, concert := range concerts {
+		i, ok := index[concert.Location.Slug]
+		if !ok {
+			i = len(groups)
+			index[concert.Location.Slug] = i
+			groups = append(groups, PlaceConcerts{Location: concert.Location})
+		}
+		groups[i].Dates = append(groups[i].Dates, concert.Date)
+	}
+	return groups
+}
+
+func sortConcerts(concerts []Concert) {
+	sort.SliceStable(concerts, func(i, j int) bool {
+		if !concerts[i].Date.Equal(concerts[j].Date) {
+			return concerts[i].Date.Before(concerts[j].Date)
+		}
+		return concerts[i].Location.Slug < concerts[j].Location.Slug
+	})
+}
@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"groupie-tracker/internal/httpclient"
+	"groupie-tracker/internal/textnorm"
+)
+
+const federatedIDStride = 10000
+
+type Upstream struct {
+	Name   string
+	Source DataSource
+}
+
+type UpstreamHealth struct {
+	Name        string                     `json:"name"`
+	LastSuccess time.Time                  `json:"lastSuccess"`
+	LastAttempt time.Time                  `json:"lastAttempt"`
+	LastError   string                     `json:"lastError,omitempty"`
+	Stale       bool                       `json:"stale"`
+	Artists     int                        `json:"artists"`
+	Duplicates  int                        `json:"duplicates"`
+	Collisions  int                        `json:"collisions"`
+	Breakers    []httpclient.BreakerStatus `json:"breakers,omitempty"`
+	Drift       []DriftWarning             `json:"drift,omitempty"`
+}
+
+type FederatedSource struct {
+	Upstreams []Upstream
+
+	fetchMu sync.Mutex
+	mu      sync.Mutex
+	last    map[string]*APIData
+	health  map[string]*UpstreamHealth
+}
+
+func NewFederatedSource(upstreams ...Upstream) *FederatedSource {
+	return &FederatedSource{
+		Upstreams: upstreams,
+		last:      make(map[string]*APIData),
+		health:    make(map[string]*UpstreamHealth),
+	}
+}
+
+func openFederated(kind, locations string) (DataSource, error) {
+	var upstreams []Upstream
+	names := make(map[string]bool)
+	for _, entry := range strings.Split(locations, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, location, ok := strings.Cut(entry, "=")
+		if !ok {
+			name, location = upstreamName(entry), entry
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate upstream name %q", name)
+		}
+		names[name] = true
+
+		source, err := openSource(kind, location)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %s: %w", name, err)
+		}
+		upstreams = append(upstreams, Upstream{Name: name, Source: source})
+	}
+	if len(upstreams) == 0 {
+		return nil, fmt.Errorf("no upstreams configured")
+	}
+	return NewFederatedSource(upstreams...), nil
+}
+
+func upstreamName(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return filepath.Base(location)
+}
+
+func (s *FederatedSource) Fetch(ctx context.Context) (*APIData, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	results := make([]*APIData, len(s.Upstreams))
+	errs := make([]error, len(s.Upstreams))
+
+	var wg sync.WaitGroup
+	wg.Add(len(s.Upstreams))
+	for i, upstream := range s.Upstreams {
+		go func(i int, source DataSource) {
+			defer wg.Done()
+			results[i], errs[i] = source.Fetch(ctx)
+		}(i, upstream.Source)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var failures []error
+	for i, upstream := range s.Upstreams {
+		health := s.health[upstream.Name]
+		if health == nil {
+			health = &UpstreamHealth{Name: upstream.Name}
+			s.health[upstream.Name] = health
+		}
+		health.LastAttempt = now
+
+		data, err := s.resolve(upstream.Name, results[i], errs[i])
+		results[i] = data
+		health.Stale = err != nil && data != nil
+		if err != nil {
+			health.LastError = err.Error()
+			failures = append(failures, fmt.Errorf("%s: %w", upstream.Name, err))
+			log.Printf("Upstream %s failed: %v", upstream.Name, err)
+		} else {
+			health.LastError = ""
+			health.LastSuccess = now
+		}
+	}
+
+	merged, contributed := s.merge(results)
+	if contributed == 0 {
+		return nil, fmt.Errorf("all upstreams failed: %w", errors.Join(failures...))
+	}
+	return merged, nil
+}
+
+func (s *FederatedSource) resolve(name string, data *APIData, err error) (*APIData, error) {
+	last := s.last[name]
+
+	var sectionErr *SectionError
+	if data != nil && errors.As(err, &sectionErr) {
+		if last == nil {
+			if sectionErr.Errors[artistsEndpoint] != nil {
+				return nil, err
+			}
+			s.last[name] = data
+			return data, err
+		}
+		for section := range sectionErr.Errors {
+			copySection(data, last, section)
+		}
+		s.last[name] = data
+		return data, err
+	}
+
+	if err != nil {
+		return last, err
+	}
+	s.last[name] = data
+	return data, nil
+}
+
+func (s *FederatedSource) merge(results []*APIData) (*APIData, int) {
+	merged := &APIData{}
+	seen := make(map[string]string)
+	taken := make(map[int]bool)
+	contributed := 0
+
+	for i, data := range results {
+		upstream := s.Upstreams[i]
+		health := s.health[upstream.Name]
+		health.Artists, health.Duplicates, health.Collisions = 0, 0, 0
+		if data == nil {
+			continue
+		}
+		contributed++
+
+		offset := i * federatedIDStride
+		ids := make(map[int]int)
+		for _, artist := range data.Artists {
+			key := federationKey(artist)
+			if _, ok := seen[key]; ok {
+				health.Duplicates++
+				continue
+			}
+			id := artist.ID + offset
+			if taken[id] {
+				log.Printf("Upstream %s: artist %d collides with an existing ID, skipping", upstream.Name, artist.ID)
+				health.Collisions++
+				continue
+			}
+			seen[key] = upstream.Name
+			taken[id] = true
+			ids[artist.ID] = id
+
+			artist.ID = id
+			artist.Source = upstream.Name
+			merged.Artists = append(merged.Artists, artist)
+			health.Artists++
+		}
+		remapSections(merged, data, ids)
+	}
+	return merged, contributed
+}
+
+func (s *FederatedSource) Seed(data *APIData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, upstream := range s.Upstreams {
+		if s.last[upstream.Name] != nil {
+			continue
+		}
+
+		offset := i * federatedIDStride
+		seeded := &APIData{}
+		ids := make(map[int]int)
+		for _, artist := range data.Artists {
+			if artist.Source != upstream.Name {
+				continue
+			}
+			ids[artist.ID] = artist.ID - offset
+			artist.ID -= offset
+			artist.Source = ""
+			seeded.Artists = append(seeded.Artists, artist)
+		}
+		if len(seeded.Artists) > 0 {
+			remapSections(seeded, data, ids)
+			s.last[upstream.Name] = seeded
+		}
+	}
+}
+
+func remapSections(dst, src *APIData, ids map[int]int) {
+	for _, loc := range src.Locations.Index {
+		if id, ok := ids[loc.ID]; ok {
+			loc.ID = id
+			dst.Locations.Index = append(dst.Locations.Index, loc)
+		}
+	}
+	for _, date := range src.Dates.Index {
+		if id, ok := ids[date.ID]; ok {
+			date.ID = id
+			dst.Dates.Index = append(dst.Dates.Index, date)
+		}
+	}
+	for _, rel := range src.Relations.Index {
+		if id, ok := ids[rel.ID]; ok {
+			rel.ID = id
+			dst.Relations.Index = append(dst.Relations.Index, rel)
+		}
+	}
+}
+
+func federationKey(artist Artist) string {
+	members := make([]string, len(artist.Members))
+	for i, member := range artist.Members {
+		members[i] = textnorm.Fold(member)
+	}
+	sort.Strings(members)
+	return textnorm.Fold(artist.Name) + "|" + strings.Join(members, "|")
+}
+
+func (s *FederatedSource) Health() []UpstreamHealth {
+	statuses := make([]UpstreamHealth, 0, len(s.Upstreams))
+	for _, upstream := range s.Upstreams {
+		status := UpstreamHealth{Name: upstream.Name}
+		s.mu.Lock()
+		if health := s.health[upstream.Name]; health != nil {
+			status = *health
+		}
+		s.mu.Unlock()
+		if reporter, ok := upstream.Source.(interface {
+			Breakers() []httpclient.BreakerStatus
+		}); ok {
+			status.Breakers = reporter.Breakers()
+		}
+		if reporter, ok := upstream.Source.(interface{ SchemaDrift() []DriftWarning }); ok {
+			status.Drift = reporter.SchemaDrift()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (s *FederatedSource) UseTransport(transport http.RoundTripper) {
+	for _, upstream := range s.Upstreams {
+		if source, ok := upstream.Source.(interface{ UseTransport(http.RoundTripper) }); ok {
+			source.UseTransport(transport)
+		}
+	}
+}
+
+func (s *FederatedSource) Degraded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, health := range s.health {
+		if health.LastError != "" {
+			return true
+		}
+	}
+	return false
+}
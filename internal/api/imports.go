@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"groupie-tracker/internal/textnorm"
+)
+
+const (
+	ImportIDBase  = 1 << 30
+	importIDRange = 1 << 30
+)
+
+var importColumns = []string{"name", "members", "creationDate", "firstAlbum", "image", "location", "date"}
+
+type ImportedConcert struct {
+	Location string `json:"location"`
+	Date     string `json:"date"`
+}
+
+type ImportedArtist struct {
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	Members      []string          `json:"members"`
+	CreationDate int               `json:"creationDate"`
+	FirstAlbum   string            `json:"firstAlbum"`
+	Concerts     []ImportedConcert `json:"concerts"`
+
+	source string
+}
+
+type Imports struct {
+	Artists []ImportedArtist
+}
+
+func LoadImports(dir string) (*Imports, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := &Imports{}
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		var artists []ImportedArtist
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			artists, err = readImportJSON(path)
+		case ".csv":
+			artists, err = readImportCSV(path)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for i := range artists {
+			artist := &artists[i]
+			artist.source = "import/" + entry.Name()
+			if err := artist.validate(); err != nil {
+				return nil, fmt.Errorf("%s: artist %q: %w", path, artist.Name, err)
+			}
+			key := textnorm.Fold(artist.Name)
+			if other, ok := seen[key]; ok {
+				return nil, fmt.Errorf("%s: artist %q is already imported from %s", path, artist.Name, other)
+			}
+			seen[key] = entry.Name()
+		}
+		imports.Artists = append(imports.Artists, artists...)
+	}
+	return imports, nil
+}
+
+func readImportJSON(path string) ([]ImportedArtist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var file struct {
+		Artists []ImportedArtist `json:"artists"`
+	}
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+	return file.Artists, nil
+}
+
+func readImportCSV(path string) ([]ImportedArtist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var artists []ImportedArtist
+	byName := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[name]])
+		}
+
+		name := field("name")
+		if name == "" {
+			return nil, fmt.Errorf("line %d: missing name", line)
+		}
+
+		i, ok := byName[normalizeKey(name)]
+		if !ok {
+			artist := ImportedArtist{
+				Name:       name,
+				Image:      field("image"),
+				FirstAlbum: field("firstAlbum"),
+			}
+			for _, member := range strings.Split(field("members"), ";") {
+				if member = strings.TrimSpace(member); member != "" {
+					artist.Members = append(artist.Members, member)
+				}
+			}
+			if created := field("creationDate"); created != "" {
+				year, err := strconv.Atoi(created)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid creationDate %q", line, created)
+				}
+				artist.CreationDate = year
+			}
+			i = len(artists)
+			byName[normalizeKey(name)] = i
+			artists = append(artists, artist)
+		}
+
+		location, date := field("location"), field("date")
+		if location != "" || date != "" {
+			artists[i].Concerts = append(artists[i].Concerts, ImportedConcert{Location: location, Date: date})
+		}
+	}
+	return artists, nil
+}
+
+func (a *ImportedArtist) validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("missing name")
+	}
+	if len(a.Members) == 0 {
+		return fmt.Errorf("no members")
+	}
+	if a.CreationDate <= 0 {
+		return fmt.Errorf("missing creationDate")
+	}
+	if _, err := ParseDate(a.FirstAlbum); err != nil {
+		return fmt.Errorf("invalid firstAlbum %q", a.FirstAlbum)
+	}
+	for _, concert := range a.Concerts {
+		if concert.Location == "" || strings.ContainsAny(concert.Location, " ,") {
+			return fmt.Errorf("invalid location %q, expected a slug like city-country", concert.Location)
+		}
+		if _, err := ParseDate(concert.Date); err != nil {
+			return fmt.Errorf("invalid concert date %q", concert.Date)
+		}
+	}
+	return nil
+}
+
+func (imp *Imports) Merge(data *APIData) *APIData {
+	if imp == nil || len(imp.Artists) == 0 {
+		return data
+	}
+
+	taken := make(map[int]bool, len(data.Artists))
+	names := make(map[string]bool, len(data.Artists))
+	for _, artist := range data.Artists {
+		taken[artist.ID] = true
+		names[textnorm.Fold(artist.Name)] = true
+	}
+
+	result := *data
+	result.Artists = append([]Artist(nil), data.Artists...)
+	result.Locations.Index = append([]Location(nil), data.Locations.Index...)
+	result.Dates.Index = append([]Date(nil), data.Dates.Index...)
+	result.Relations.Index = append([]Relation(nil), data.Relations.Index...)
+
+	for _, imported := range imp.Artists {
+		if names[textnorm.Fold(imported.Name)] {
+			log.Printf("Import %s: artist %q already exists upstream, skipping", imported.source, imported.Name)
+			continue
+		}
+		id := importID(imported.Name, taken)
+		taken[id] = true
+
+		result.Artists = append(result.Artists, Artist{
+			ID:           id,
+			Image:        imported.Image,
+			Name:         imported.Name,
+			Members:      append([]string(nil), imported.Members...),
+			CreationDate: imported.CreationDate,
+			FirstAlbum:   imported.FirstAlbum,
+			Source:       imported.source,
+		})
+
+		relation := Relation{ID: id, DatesLocations: make(map[string][]string)}
+		for _, concert := range imported.Concerts {
+			relation.DatesLocations[concert.Location] = append(relation.DatesLocations[concert.Location], canonicalDate(concert.Date))
+		}
+
+		location := Location{ID: id, Locations: sortedKeys(relation.DatesLocations)}
+		date := Date{ID: id}
+		for _, slug := range location.Locations {
+			date.Dates = append(date.Dates, relation.DatesLocations[slug]...)
+		}
+
+		result.Locations.Index = append(result.Locations.Index, location)
+		result.Dates.Index = append(result.Dates.Index, date)
+		result.Relations.Index = append(result.Relations.Index, relation)
+	}
+	return &result
+}
+
+func importID(name string, taken map[int]bool) int {
+	h := fnv.New32a()
+	h.Write([]byte(textnorm.Fold(name)))
+	offset := int(h.Sum32() % importIDRange)
+	for taken[ImportIDBase+offset] {
+		offset = (offset + 1) % importIDRange
+	}
+	return ImportIDBase + offset
+}
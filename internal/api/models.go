@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 type Artist struct {
 	ID           int      `json:"id"`
 	Image        string   `json:"image"`
@@ -10,6 +12,9 @@ type Artist struct {
 	Locations    string   `json:"locations"`
 	ConcertDates string   `json:"concertDates"`
 	Relations    string   `json:"relations"`
+	Source       string   `json:"source,omitempty"`
+
+	FirstAlbumDate time.Time `json:"-"`
 }
 
 type Location struct {
@@ -41,8 +46,8 @@ type RelationIndex struct {
 }
 
 type APIData struct {
-	Artists   []Artist
-	Locations LocationIndex
-	Dates     DateIndex
-	Relations RelationIndex
+	Artists   []Artist      `json:"artists"`
+	Locations LocationIndex `json:"locations"`
+	Dates     DateIndex     `json:"dates"`
+	Relations RelationIndex `json:"relations"`
 }
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	PatchName         = "name"
+	PatchImage        = "image"
+	PatchFirstAlbum   = "firstAlbum"
+	PatchCreationDate = "creationDate"
+	PatchMember       = "member"
+	PatchLocation     = "location"
+)
+
+type Patch struct {
+	ArtistID int             `json:"artist"`
+	Field    string          `json:"field"`
+	Match    json.RawMessage `json:"match,omitempty"`
+	Value    json.RawMessage `json:"value"`
+	Note     string          `json:"note,omitempty"`
+
+	match string
+	value string
+	year  int
+}
+
+type StalePatch struct {
+	Patch  Patch  `json:"patch"`
+	Reason string `json:"reason"`
+}
+
+type Overrides struct {
+	Patches []Patch `json:"patches"`
+}
+
+func LoadOverrides(path string) (*Overrides, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var overrides Overrides
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i := range overrides.Patches {
+		if err := overrides.Patches[i].parse(); err != nil {
+			return nil, fmt.Errorf("%s: patch %d: %w", path, i+1, err)
+		}
+	}
+	return &overrides, nil
+}
+
+func (p *Patch) parse() error {
+	if p.ArtistID <= 0 {
+		return fmt.Errorf("missing artist ID")
+	}
+	if len(p.Match) > 0 {
+		if err := json.Unmarshal(p.Match, &p.match); err != nil {
+			return fmt.Errorf("match must be a string")
+		}
+	}
+
+	switch p.Field {
+	case PatchCreationDate:
+		if err := json.Unmarshal(p.Value, &p.year); err != nil {
+			return fmt.Errorf("%s value must be a number", p.Field)
+		}
+		return nil
+	case PatchName, PatchImage, PatchFirstAlbum:
+	case PatchMember, PatchLocation:
+		if p.match == "" {
+			return fmt.Errorf("%s patch needs a match", p.Field)
+		}
+	default:
+		return fmt.Errorf("unknown field %q", p.Field)
+	}
+
+	if err := json.Unmarshal(p.Value, &p.value); err != nil {
+		return fmt.Errorf("%s value must be a string", p.Field)
+	}
+	if p.Field == PatchLocation && p.value == "" {
+		return fmt.Errorf("location value must not be empty")
+	}
+	return nil
+}
+
+func (o *Overrides) Apply(data *APIData) (*APIData, []StalePatch) {
+	if o == nil || len(o.Patches) == 0 {
+		return data, nil
+	}
+
+	result := *data
+	result.Artists = append([]Artist(nil), data.Artists...)
+	result.Locations.Index = append([]Location(nil), data.Locations.Index...)
+	result.Relations.Index = append([]Relation(nil), data.Relations.Index...)
+
+	var stale []StalePatch
+	for _, patch := range o.Patches {
+		if reason := patch.apply(&result); reason != "" {
+			stale = append(stale, StalePatch{Patch: patch, Reason: reason})
+		}
+	}
+	return &result, stale
+}
+
+func (p Patch) apply(data *APIData) string {
+	var artist *Artist
+	for i := range data.Artists {
+		if data.Artists[i].ID == p.ArtistID {
+			artist = &data.Artists[i]
+		}
+	}
+	if artist == nil {
+		return fmt.Sprintf("artist %d not found", p.ArtistID)
+	}
+
+	switch p.Field {
+	case PatchName:
+		return setString(&artist.Name, p.match, p.value)
+	case PatchImage:
+		return setString(&artist.Image, p.match, p.value)
+	case PatchFirstAlbum:
+		return setString(&artist.FirstAlbum, p.match, p.value)
+	case PatchCreationDate:
+		if p.match != "" && fmt.Sprint(artist.CreationDate) != p.match {
+			return fmt.Sprintf("creationDate is %d, not %s", artist.CreationDate, p.match)
+		}
+		artist.CreationDate = p.year
+	case PatchMember:
+		return patchMember(artist, p.match, p.value)
+	case PatchLocation:
+		return patchLocation(data, p.ArtistID, p.match, p.value)
+	}
+	return ""
+}
+
+func setString(field *string, match, value string) string {
+	if match != "" && *field != match {
+		return fmt.Sprintf("value is %q, not %q", *field, match)
+	}
+	*field = value
+	return ""
+}
+
+func patchMember(artist *Artist, match, value string) string {
+	members := make([]string, 0, len(artist.Members))
+	found := false
+	for _, member := range artist.Members {
+		if member == match && !found {
+			found = true
+			if value != "" {
+				members = append(members, value)
+			}
+			continue
+		}
+		members = append(members, member)
+	}
+	if !found {
+		return fmt.Sprintf("member %q not found", match)
+	}
+	artist.Members = members
+	return ""
+}
+
+func patchLocation(data *APIData, id int, match, value string) string {
+	found := false
+
+	for i := range data.Relations.Index {
+		rel := &data.Relations.Index[i]
+		dates, ok := rel.DatesLocations[match]
+		if rel.ID != id || !ok {
+			continue
+		}
+		patched := make(map[string][]string, len(rel.DatesLocations))
+		for slug, list := range rel.DatesLocations {
+			if slug != match {
+				patched[slug] = list
+			}
+		}
+		patched[value] = append(append([]string(nil), patched[value]...), dates...)
+		rel.DatesLocations = patched
+		found = true
+	}
+
+	for i := range data.Locations.Index {
+		loc := &data.Locations.Index[i]
+		if loc.ID != id {
+			continue
+		}
+		patched := make([]string, len(loc.Locations))
+		for j, slug := range loc.Locations {
+			if slug == match {
+				slug = value
+				found = true
+			}
+			patched[j] = slug
+		}
+		loc.Locations = patched
+	}
+
+	if !found {
+		return fmt.Sprintf("location %q not found", match)
+	}
+	return ""
+}
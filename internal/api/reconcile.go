@@ -0,0 +1,183 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func Reconcile(data *APIData) (*APIData, []Anomaly) {
+	names := make(map[int]string, len(data.Artists))
+	sources := make(map[int]string, len(data.Artists))
+	ids := make(map[int]bool)
+	for _, artist := range data.Artists {
+		names[artist.ID] = artist.Name
+		sources[artist.ID] = artist.Source
+		ids[artist.ID] = true
+	}
+
+	locations := make(map[int]Location, len(data.Locations.Index))
+	for _, loc := range data.Locations.Index {
+		locations[loc.ID] = loc
+		ids[loc.ID] = true
+	}
+	dates := make(map[int]Date, len(data.Dates.Index))
+	for _, date := range data.Dates.Index {
+		dates[date.ID] = date
+		ids[date.ID] = true
+	}
+	relations := make(map[int]Relation, len(data.Relations.Index))
+	for _, rel := range data.Relations.Index {
+		relations[rel.ID] = rel
+		ids[rel.ID] = true
+	}
+
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+
+	var anomalies []Anomaly
+	flag := func(id int, field, value, problem string, repaired bool) {
+		anomalies = append(anomalies, Anomaly{
+			ArtistID: id,
+			Artist:   names[id],
+			Field:    field,
+			Value:    value,
+			Problem:  problem,
+			Repaired: repaired,
+		})
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		if sources[sorted[i-1]] != sources[sorted[i]] {
+			continue
+		}
+		for gap := sorted[i-1] + 1; gap < sorted[i]; gap++ {
+			flag(gap, "id", fmt.Sprint(gap), "ID gap", false)
+		}
+	}
+
+	result := *data
+	result.Relations = RelationIndex{Index: make([]Relation, 0, len(data.Relations.Index))}
+
+	for _, id := range sorted {
+		_, hasArtist := names[id]
+		loc, hasLocations := locations[id]
+		date, hasDates := dates[id]
+		rel, hasRelation := relations[id]
+
+		if !hasArtist {
+			flag(id, "id", fmt.Sprint(id), "no artist record", false)
+		}
+		if !hasLocations {
+			flag(id, "id", fmt.Sprint(id), "no locations record", false)
+		}
+		if !hasDates {
+			flag(id, "id", fmt.Sprint(id), "no dates record", false)
+		}
+
+		if !hasRelation || len(rel.DatesLocations) == 0 {
+			problem := "no relation record"
+			if hasRelation {
+				problem = "empty relation record"
+			}
+			rebuilt, ok := rebuildRelation(id, loc, date)
+			if ok {
+				flag(id, "relation", fmt.Sprint(id), problem+", rebuilt from locations and dates", true)
+				result.Relations.Index = append(result.Relations.Index, rebuilt)
+				continue
+			}
+			flag(id, "relation", fmt.Sprint(id), problem, false)
+			if hasRelation {
+				result.Relations.Index = append(result.Relations.Index, rel)
+			}
+			continue
+		}
+
+		result.Relations.Index = append(result.Relations.Index, rel)
+
+		if hasLocations {
+			listed := make(map[string]bool, len(loc.Locations))
+			for _, slug := range loc.Locations {
+				listed[slug] = true
+				if len(rel.DatesLocations[slug]) == 0 {
+					flag(id, "locations", slug, "location with no dates", false)
+				}
+			}
+			for _, slug := range sortedKeys(rel.DatesLocations) {
+				if !listed[slug] {
+					flag(id, "locations", slug, "location missing from locations index", false)
+				}
+			}
+		}
+
+		if hasDates {
+			played := make(map[string]bool)
+			for _, list := range rel.DatesLocations {
+				for _, d := range list {
+					played[canonicalDate(d)] = true
+				}
+			}
+			known := make(map[string]bool, len(date.Dates))
+			for _, d := range date.Dates {
+				key := canonicalDate(d)
+				known[key] = true
+				if !played[key] {
+					flag(id, "dates", d, "date with no location", false)
+				}
+			}
+			for _, slug := range sortedKeys(rel.DatesLocations) {
+				for _, d := range rel.DatesLocations[slug] {
+					if !known[canonicalDate(d)] {
+						flag(id, "dates", d, "date missing from dates index", false)
+					}
+				}
+			}
+		}
+	}
+
+	return &result, anomalies
+}
+
+func rebuildRelation(id int, loc Location, date Date) (Relation, bool) {
+	if len(loc.Locations) == 0 || len(date.Dates) == 0 {
+		return Relation{}, false
+	}
+
+	var groups [][]string
+	for i, d := range date.Dates {
+		if i == 0 || strings.HasPrefix(strings.TrimSpace(d), "*") {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], canonicalDate(d))
+	}
+
+	if len(groups) != len(loc.Locations) {
+		return Relation{}, false
+	}
+
+	rel := Relation{ID: id, DatesLocations: make(map[string][]string, len(groups))}
+	for i, slug := range loc.Locations {
+		rel.DatesLocations[slug] = append(rel.DatesLocations[slug], groups[i]...)
+	}
+	return rel, true
+}
+
+func canonicalDate(s string) string {
+	t, _, err := ParseDateTolerant(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return t.Format("02-01-2006")
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DriftAdded   = "added"
+	DriftMissing = "missing"
+	DriftType    = "type changed"
+	DriftEmpty   = "empty"
+)
+
+var DriftThreshold = 0.1
+
+type sectionSchema struct {
+	index  bool
+	fields map[string]string
+}
+
+var schemaManifest = map[string]sectionSchema{
+	artistsEndpoint: {fields: map[string]string{
+		"id":           "number",
+		"image":        "string",
+		"name":         "string",
+		"members":      "array",
+		"creationDate": "number",
+		"firstAlbum":   "string",
+		"locations":    "string",
+		"concertDates": "string",
+		"relations":    "string",
+	}},
+	locationsEndpoint: {index: true, fields: map[string]string{
+		"id":        "number",
+		"locations": "array",
+		"dates":     "string",
+	}},
+	datesEndpoint: {index: true, fields: map[string]string{
+		"id":    "number",
+		"dates": "array",
+	}},
+	relationsEndpoint: {index: true, fields: map[string]string{
+		"id":             "number",
+		"datesLocations": "object",
+	}},
+}
+
+type DriftWarning struct {
+	Section  string `json:"section"`
+	Field    string `json:"field"`
+	Kind     string `json:"kind"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Records  int    `json:"records"`
+	Total    int    `json:"total"`
+}
+
+func (w DriftWarning) String() string {
+	switch w.Kind {
+	case DriftType:
+		return fmt.Sprintf("%s.%s changed type from %s to %s in %d/%d records", w.Section, w.Field, w.Expected, w.Actual, w.Records, w.Total)
+	case DriftEmpty:
+		return fmt.Sprintf("%s has no records", w.Section)
+	default:
+		return fmt.Sprintf("%s.%s %s in %d/%d records", w.Section, w.Field, w.Kind, w.Records, w.Total)
+	}
+}
+
+func (w DriftWarning) fatal() bool {
+	switch w.Kind {
+	case DriftType, DriftEmpty:
+		return true
+	case DriftMissing:
+		return w.Total == 0 || float64(w.Records)/float64(w.Total) > DriftThreshold
+	}
+	return false
+}
+
+type DriftError struct {
+	Section  string
+	Warnings []DriftWarning
+}
+
+func (e *DriftError) Error() string {
+	var problems []string
+	for _, w := range e.Warnings {
+		if w.fatal() {
+			problems = append(problems, w.String())
+		}
+	}
+	return "schema drift: " + strings.Join(problems, "; ")
+}
+
+func CheckSchema(section string, body []byte) ([]DriftWarning, error) {
+	schema, ok := schemaManifest[section]
+	if !ok {
+		return nil, fmt.Errorf("no schema for section %q", section)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	if schema.index {
+		wrapper, ok := raw.(map[string]interface{})
+		if !ok {
+			return []DriftWarning{{Section: section, Field: "index", Kind: DriftType, Expected: "object", Actual: jsonKind(raw)}}, nil
+		}
+		raw = wrapper["index"]
+		if raw == nil {
+			return []DriftWarning{{Section: section, Field: "index", Kind: DriftMissing}}, nil
+		}
+	}
+
+	records, ok := raw.([]interface{})
+	if !ok {
+		return []DriftWarning{{Section: section, Field: "index", Kind: DriftType, Expected: "array", Actual: jsonKind(raw)}}, nil
+	}
+	if len(records) == 0 {
+		return []DriftWarning{{Section: section, Field: "index", Kind: DriftEmpty}}, nil
+	}
+
+	type key struct{ field, kind, actual string }
+	counts := make(map[key]int)
+	for _, record := range records {
+		fields, ok := record.(map[string]interface{})
+		if !ok {
+			counts[key{"", DriftType, jsonKind(record)}]++
+			continue
+		}
+		for field, expected := range schema.fields {
+			value, present := fields[field]
+			switch {
+			case !present || value == nil:
+				counts[key{field, DriftMissing, ""}]++
+			case jsonKind(value) != expected:
+				counts[key{field, DriftType, jsonKind(value)}]++
+			}
+		}
+		for field, value := range fields {
+			if _, known := schema.fields[field]; !known {
+				counts[key{field, DriftAdded, jsonKind(value)}]++
+			}
+		}
+	}
+
+	warnings := make([]DriftWarning, 0, len(counts))
+	for k, n := range counts {
+		w := DriftWarning{Section: section, Field: k.field, Kind: k.kind, Records: n, Total: len(records)}
+		if k.kind == DriftType {
+			w.Expected = schema.fields[k.field]
+			if k.field == "" {
+				w.Expected = "object"
+			}
+		}
+		if k.kind != DriftMissing {
+			w.Actual = k.actual
+		}
+		warnings = append(warnings, w)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Field != warnings[j].Field {
+			return warnings[i].Field < warnings[j].Field
+		}
+		return warnings[i].Kind < warnings[j].Kind
+	})
+	return warnings, nil
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+type driftLog struct {
+	mu       sync.Mutex
+	sections map[string][]DriftWarning
+}
+
+func (d *driftLog) decode(section string, body []byte, target interface{}) error {
+	warnings, err := CheckSchema(section, body)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	if d.sections == nil {
+		d.sections = make(map[string][]DriftWarning)
+	}
+	d.sections[section] = warnings
+	d.mu.Unlock()
+
+	for _, w := range warnings {
+		log.Printf("Schema drift: %s", w)
+	}
+	added := false
+	for _, w := range warnings {
+		if w.fatal() {
+			return &DriftError{Section: section, Warnings: warnings}
+		}
+		added = added || w.Kind == DriftAdded
+	}
+	if added {
+		if body, err = knownFields(section, body); err != nil {
+			return err
+		}
+	}
+	return decodeStrict(body, target)
+}
+
+func knownFields(section string, body []byte) ([]byte, error) {
+	schema := schemaManifest[section]
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	records := raw
+	if schema.index {
+		wrapper, _ := raw.(map[string]interface{})
+		records = wrapper["index"]
+	}
+	list, _ := records.([]interface{})
+	for _, record := range list {
+		fields, _ := record.(map[string]interface{})
+		for field := range fields {
+			if _, known := schema.fields[field]; !known {
+				delete(fields, field)
+			}
+		}
+	}
+	return json.Marshal(raw)
+}
+
+func decodeStrict(body []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the JSON document")
+	}
+	return nil
+}
+
+func (d *driftLog) warnings() []DriftWarning {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var all []DriftWarning
+	for _, name := range sectionNames {
+		all = append(all, d.sections[name]...)
+	}
+	return all
+}
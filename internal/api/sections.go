@@ -0,0 +1,102 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+var sectionNames = []string{artistsEndpoint, locationsEndpoint, datesEndpoint, relationsEndpoint}
+
+type SectionStatus struct {
+	Name      string    `json:"name"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type SectionError struct {
+	Errors map[string]error
+}
+
+func (e *SectionError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = name + ": " + e.Errors[name].Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *SectionError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (e *SectionError) Complete() bool {
+	return len(e.Errors) == len(sectionNames)
+}
+
+func sectionTarget(data *APIData, name string) interface{} {
+	switch name {
+	case artistsEndpoint:
+		return &data.Artists
+	case locationsEndpoint:
+		return &data.Locations
+	case datesEndpoint:
+		return &data.Dates
+	case relationsEndpoint:
+		return &data.Relations
+	}
+	return nil
+}
+
+func copySection(dst, src *APIData, name string) {
+	copyTarget(sectionTarget(dst, name), sectionTarget(src, name))
+}
+
+func copyTarget(dst, src interface{}) {
+	switch dst := dst.(type) {
+	case *[]Artist:
+		*dst = *src.(*[]Artist)
+	case *LocationIndex:
+		*dst = *src.(*LocationIndex)
+	case *DateIndex:
+		*dst = *src.(*DateIndex)
+	case *RelationIndex:
+		*dst = *src.(*RelationIndex)
+	}
+}
+
+func mergeSections(data *APIData, fetched time.Time, failed map[string]error, previous *Catalog) []SectionStatus {
+	empty := &APIData{}
+	statuses := make([]SectionStatus, len(sectionNames))
+
+	for i, name := range sectionNames {
+		statuses[i] = SectionStatus{Name: name, FetchedAt: fetched}
+
+		err, ok := failed[name]
+		if !ok {
+			continue
+		}
+		statuses[i].Error = err.Error()
+		statuses[i].FetchedAt = time.Time{}
+
+		if previous == nil {
+			copySection(data, empty, name)
+			continue
+		}
+		copySection(data, previous.upstream, name)
+		if prev := previous.Section(name); prev != nil {
+			statuses[i].FetchedAt = prev.FetchedAt
+		}
+	}
+	return statuses
+}
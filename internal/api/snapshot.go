@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotVersion = 1
+	snapshotPrefix  = "snapshot-v"
+	defaultKeep     = 5
+)
+
+type Snapshot struct {
+	Version   int       `json:"version"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Data      *APIData  `json:"data"`
+}
+
+type SnapshotStore struct {
+	Dir  string
+	Keep int
+}
+
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{Dir: dir, Keep: defaultKeep}
+}
+
+func (s *SnapshotStore) Save(data *APIData, fetchedAt time.Time) error {
+	snapshot := Snapshot{Version: snapshotVersion, FetchedAt: fetchedAt.UTC(), Data: data}
+	name := fmt.Sprintf("%s%d-%d.json", snapshotPrefix, snapshotVersion, fetchedAt.UnixNano())
+	if err := writeJSONAtomic(filepath.Join(s.Dir, name), snapshot); err != nil {
+		return err
+	}
+
+	return s.prune()
+}
+
+func writeJSONAtomic(path string, v interface{}) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(v); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *SnapshotStore) Latest() (*Snapshot, error) {
+	files, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.version != snapshotVersion {
+			continue
+		}
+		snapshot, err := readSnapshot(file.path)
+		if err != nil || snapshot.Version != snapshotVersion || snapshot.Data == nil {
+			continue
+		}
+		return snapshot, nil
+	}
+	return nil, fmt.Errorf("no snapshot found in %s", s.Dir)
+}
+
+type snapshotFile struct {
+	path    string
+	version int
+	stamp   int64
+}
+
+func (s *SnapshotStore) list() ([]snapshotFile, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []snapshotFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), ".json"), "-", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		version, err1 := strconv.Atoi(parts[0])
+		stamp, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		files = append(files, snapshotFile{path: filepath.Join(s.Dir, name), version: version, stamp: stamp})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].stamp > files[j].stamp
+	})
+	return files, nil
+}
+
+func (s *SnapshotStore) prune() error {
+	if s.Keep <= 0 {
+		return nil
+	}
+	files, err := s.list()
+	if err != nil {
+		return err
+	}
+	for i := s.Keep; i < len(files); i++ {
+		if err := os.Remove(files[i].path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(f).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+type SnapshotSource struct {
+	Store *SnapshotStore
+}
+
+func NewSnapshotSource(store *SnapshotStore) *SnapshotSource {
+	return &SnapshotSource{Store: store}
+}
+
+func (s *SnapshotSource) Fetch(ctx context.Context) (*APIData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.Store.Latest()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Data, nil
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"groupie-tracker/internal/httpclient"
+)
+
+const DefaultBaseURL = "https://groupietrackers.herokuapp.com/api"
+
+const (
+	artistsEndpoint   = "artists"
+	locationsEndpoint = "locations"
+	datesEndpoint     = "dates"
+	relationsEndpoint = "relation"
+)
+
+type DataSource interface {
+	Fetch(ctx context.Context) (*APIData, error)
+}
+
+func OpenSource(kind, location string) (DataSource, error) {
+	if strings.Contains(location, ",") {
+		return openFederated(kind, location)
+	}
+	return openSource(kind, location)
+}
+
+func openSource(kind, location string) (DataSource, error) {
+	switch kind {
+	case "", "http":
+		if location == "" {
+			location = DefaultBaseURL
+		}
+		return NewHTTPSource(location), nil
+	case "file":
+		if location == "" {
+			return nil, fmt.Errorf("file source needs a data directory")
+		}
+		return NewFileSource(location), nil
+	case "memory":
+		if location == "" {
+			return NewMemorySource(&APIData{}), nil
+		}
+		data, err := NewFileSource(location).Fetch(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return NewMemorySource(data), nil
+	default:
+		return nil, fmt.Errorf("unknown data source %q", kind)
+	}
+}
+
+type HTTPSource struct {
+	BaseURL string
+	Client  *httpclient.Client
+
+	mu         sync.Mutex
+	last       *APIData
+	validators map[string]validator
+	drift      driftLog
+}
+
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+func NewHTTPSource(baseURL string) *HTTPSource {
+	return &HTTPSource{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Client:     httpclient.New(httpclient.DefaultConfig),
+		validators: make(map[string]validator),
+	}
+}
+
+func (s *HTTPSource) Fetch(ctx context.Context) (*APIData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var mu sync.Mutex
+	validators := make(map[string]validator, len(s.validators))
+	for endpoint, v := range s.validators {
+		validators[endpoint] = v
+	}
+	var changed, unchanged []string
+
+	data, err := fetchSections(ctx, func(ctx context.Context, endpoint string, target interface{}) error {
+		mu.Lock()
+		v, ok := validators[endpoint]
+		delete(validators, endpoint)
+		mu.Unlock()
+
+		var previous interface{}
+		if ok && s.last != nil {
+			previous = sectionTarget(s.last, endpoint)
+		} else {
+			v = validator{}
+		}
+
+		next, modified, err := s.fetchJSON(ctx, endpoint, v, target, previous)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		validators[endpoint] = next
+		if modified {
+			changed = append(changed, endpoint)
+		} else {
+			unchanged = append(unchanged, endpoint)
+		}
+		mu.Unlock()
+		return nil
+	})
+
+	s.validators = validators
+	if data != nil {
+		s.last = data
+		sort.Strings(changed)
+		sort.Strings(unchanged)
+		log.Printf("Upstream refresh: changed %v, unchanged %v", changed, unchanged)
+	}
+	return data, err
+}
+
+func (s *HTTPSource) fetchJSON(ctx context.Context, endpoint string, v validator, target, previous interface{}) (validator, bool, error) {
+	header := http.Header{}
+	if previous != nil {
+		if v.etag != "" {
+			header.Set("If-None-Match", v.etag)
+		}
+		if v.lastModified != "" {
+			header.Set("If-Modified-Since", v.lastModified)
+		}
+	}
+
+	resp, err := s.Client.Get(ctx, s.BaseURL+"/"+endpoint, header)
+	if err != nil {
+		return validator{}, false, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		copyTarget(target, previous)
+		return v, false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return validator{}, false, &httpclient.StatusError{StatusCode: resp.StatusCode, Body: string(resp.Body)}
+	}
+
+	if err := s.drift.decode(endpoint, resp.Body, target); err != nil {
+		return validator{}, false, err
+	}
+
+	next := validator{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	return next, true, nil
+}
+
+func (s *HTTPSource) Breakers() []httpclient.BreakerStatus {
+	return s.Client.Breakers()
+}
+
+func (s *HTTPSource) UseTransport(transport http.RoundTripper) {
+	s.Client.HTTP.Transport = transport
+}
+
+func (s *HTTPSource) SchemaDrift() []DriftWarning {
+	return s.drift.warnings()
+}
+
+type FileSource struct {
+	Dir string
+
+	drift driftLog
+}
+
+func NewFileSource(dir string) *FileSource {
+	return &FileSource{Dir: dir}
+}
+
+func (s *FileSource) Fetch(ctx context.Context) (*APIData, error) {
+	return fetchSections(ctx, func(ctx context.Context, endpoint string, target interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		body, err := os.ReadFile(filepath.Join(s.Dir, endpoint+".json"))
+		if err != nil {
+			return err
+		}
+		return s.drift.decode(endpoint, body, target)
+	})
+}
+
+func (s *FileSource) SchemaDrift() []DriftWarning {
+	return s.drift.warnings()
+}
+
+type MemorySource struct {
+	Data *APIData
+}
+
+func NewMemorySource(data *APIData) *MemorySource {
+	return &MemorySource{Data: data}
+}
+
+func (s *MemorySource) Fetch(ctx context.Context) (*APIData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.Data == nil {
+		return nil, fmt.Errorf("memory source is empty")
+	}
+	return s.Data, nil
+}
+
+func fetchSections(ctx context.Context, load func(ctx context.Context, endpoint string, target interface{}) error) (*APIData, error) {
+	data := &APIData{}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := make(map[string]error)
+
+	wg.Add(len(sectionNames))
+	for _, name := range sectionNames {
+		go func(endpoint string, target interface{}) {
+			defer wg.Done()
+			if err := load(ctx, endpoint, target); err != nil {
+				mu.Lock()
+				failed[endpoint] = err
+				mu.Unlock()
+			}
+		}(name, sectionTarget(data, name))
+	}
+
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return data, nil
+	}
+	err := &SectionError{Errors: failed}
+	if err.Complete() {
+		return nil, err
+	}
+	return data, err
+}
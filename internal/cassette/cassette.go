@@ -0,0 +1,182 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Mode int
+
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+	ModeStrict
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "strict":
+		return ModeStrict, nil
+	}
+	return 0, fmt.Errorf("unknown cassette mode %q", s)
+}
+
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Recorder struct {
+	Path string
+	Mode Mode
+	Next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode, Next: http.DefaultTransport}
+
+	body, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && mode != ModeStrict:
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(body, &r.interactions); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := Request{Method: req.Method, URL: req.URL.String()}
+
+	if r.Mode != ModeRecord {
+		if interaction, ok := r.find(key); ok {
+			return interaction.Response.toHTTP(req), nil
+		}
+		if r.Mode == ModeStrict {
+			return nil, fmt.Errorf("%s %s: %w in %s", key.Method, key.URL, ErrNoInteraction, r.Path)
+		}
+	}
+
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Date")
+	header.Del("Set-Cookie")
+	recorded := Response{StatusCode: resp.StatusCode, Header: header, Body: string(body)}
+	if r.Mode == ModeRecord {
+		if err := r.record(Interaction{Request: key, Response: recorded}); err != nil {
+			return nil, err
+		}
+	}
+	return recorded.toHTTP(req), nil
+}
+
+func (r *Recorder) find(key Request) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interaction := range r.interactions {
+		if interaction.Request == key {
+			return interaction, true
+		}
+	}
+	return Interaction{}, false
+}
+
+func (r *Recorder) record(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replaced := false
+	for i := range r.interactions {
+		if r.interactions[i].Request != interaction.Request {
+			continue
+		}
+		if interaction.Response.StatusCode == http.StatusNotModified {
+			return nil
+		}
+		r.interactions[i] = interaction
+		replaced = true
+	}
+	if !replaced {
+		r.interactions = append(r.interactions, interaction)
+	}
+
+	body, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := r.Path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.Path)
+}
+
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+func (resp Response) toHTTP(req *http.Request) *http.Response {
+	body := []byte(resp.Body)
+	header := resp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
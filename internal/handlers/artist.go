@@ -11,11 +11,11 @@ import (
 )
 
 type ArtistData struct {
-	Artist   *api.Artist
-	Relation *api.Relation
+	Artist *api.Artist
+	Tour   []api.PlaceConcerts
 }
 
-func ArtistHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) ArtistHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
 		return
@@ -28,23 +28,25 @@ func ArtistHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	artist, err := api.GetArtistByID(id)
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	catalog, err := h.client.CatalogContext(ctx)
 	if err != nil {
-		log.Println("Error fetching artist:", err)
-		utils.ErrorHandler(w, http.StatusNotFound)
+		log.Println("Error fetching data:", err)
+		utils.ErrorHandler(w, errorStatus(err))
 		return
 	}
 
-	relation, err := api.GetRelationByID(id)
-	if err != nil {
-		log.Println("Error fetching relation:", err)
-		utils.ErrorHandler(w, http.StatusInternalServerError)
+	artist, ok := catalog.Artist(id)
+	if !ok {
+		utils.ErrorHandler(w, http.StatusNotFound)
 		return
 	}
 
 	artistData := ArtistData{
-		Artist:   artist,
-		Relation: relation,
+		Artist: artist,
+		Tour:   catalog.Tour(id),
 	}
 
 	pageData := utils.PageData{
@@ -52,6 +54,7 @@ func ArtistHandler(w http.ResponseWriter, r *http.Request) {
 		ActiveTab:       "",
 		ContentTemplate: "artist",
 		Data:            artistData,
+		Outdated:        h.client.Degraded(),
 	}
 
 	if err := utils.RenderTemplate(w, "artist.html", pageData); err != nil {
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/utils"
+)
+
+type ChangesData struct {
+	Changes []api.Change `json:"changes"`
+	Cursor  int          `json:"cursor"`
+}
+
+func (h *Handler) changes(r *http.Request) ChangesData {
+	changes, cursor := h.client.Changes(parseIntParam(r, "since", 0))
+	if changes == nil {
+		changes = []api.Change{}
+	}
+	return ChangesData{Changes: changes, Cursor: cursor}
+}
+
+func (h *Handler) ChangesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := h.changes(r)
+	for i, j := 0, len(data.Changes)-1; i < j; i, j = i+1, j-1 {
+		data.Changes[i], data.Changes[j] = data.Changes[j], data.Changes[i]
+	}
+
+	pageData := utils.PageData{
+		Title:           "Changes",
+		ContentTemplate: "changes",
+		Outdated:        h.client.Degraded(),
+		Data:            data,
+	}
+
+	if err := utils.RenderTemplate(w, "changes.html", pageData); err != nil {
+		log.Println("Error rendering template:", err)
+		utils.ErrorHandler(w, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ChangesAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.changes(r))
+}
@@ -7,7 +7,7 @@ import (
 	"groupie-tracker/internal/utils"
 )
 
-func GeoHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GeoHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
 		return
@@ -17,6 +17,7 @@ func GeoHandler(w http.ResponseWriter, r *http.Request) {
 		Title:           "Map",
 		ActiveTab:       "map",
 		ContentTemplate: "map",
+		Outdated:        h.client.Degraded(),
 	}
 
 	if err := utils.RenderTemplate(w, "map.html", pageData); err != nil {
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+)
+
+const defaultRequestTimeout = 15 * time.Second
+
+type Handler struct {
+	RequestTimeout time.Duration
+
+	client   *api.Client
+	services *services.Service
+}
+
+func New(client *api.Client) *Handler {
+	return &Handler{
+		RequestTimeout: defaultRequestTimeout,
+		client:         client,
+		services:       services.New(client),
+	}
+}
+
+func (h *Handler) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if h.RequestTimeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	return context.WithTimeout(r.Context(), h.RequestTimeout)
+}
+
+func errorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
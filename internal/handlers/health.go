@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/httpclient"
+	"groupie-tracker/internal/services"
+	"groupie-tracker/internal/utils"
+)
+
+type HealthData struct {
+	Cache    api.CacheStatus            `json:"cache"`
+	Upstream []httpclient.BreakerStatus `json:"upstream,omitempty"`
+	Sources  []api.UpstreamHealth       `json:"sources,omitempty"`
+	Drift    []api.DriftWarning         `json:"drift,omitempty"`
+	Geocoder []httpclient.BreakerStatus `json:"geocoder,omitempty"`
+}
+
+func (h *Handler) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	health := HealthData{
+		Cache:    h.client.Status(),
+		Upstream: h.client.UpstreamStatus(),
+		Sources:  h.client.SourceHealth(),
+		Drift:    h.client.SchemaDrift(),
+		Geocoder: services.GeocoderStatus(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !health.Cache.Loaded {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(health)
+}
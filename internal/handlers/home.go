@@ -9,7 +9,7 @@ import (
 	"groupie-tracker/internal/utils"
 )
 
-func HomeHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) HomeHandler(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
 		utils.ErrorHandler(w, http.StatusNotFound)
 		return
@@ -20,6 +20,9 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
 	hasFilters := r.URL.RawQuery != ""
 
 	var artists []api.Artist
@@ -35,18 +38,18 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 			MembersMax:      parseIntParam(r, "members_max", 100),
 			Locations:       parseArrayParam(r, "location"),
 		}
-		artists, err = services.ApplyFilters(filters)
+		artists, err = h.services.ApplyFiltersContext(ctx, filters)
 	} else {
-		var data *api.APIData
-		data, err = api.FetchAPI()
+		var catalog *api.Catalog
+		catalog, err = h.client.CatalogContext(ctx)
 		if err == nil {
-			artists = data.Artists
+			artists = catalog.Artists()
 		}
 	}
 
 	if err != nil {
 		log.Println("Error fetching data:", err)
-		utils.ErrorHandler(w, http.StatusInternalServerError)
+		utils.ErrorHandler(w, errorStatus(err))
 		return
 	}
 
@@ -54,6 +57,7 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 		ActiveTab:       "home",
 		ContentTemplate: "index",
 		Data:            artists,
+		Outdated:        h.client.Degraded(),
 	}
 
 	if err := utils.RenderTemplate(w, "index.html", pageData); err != nil {
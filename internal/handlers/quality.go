@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"groupie-tracker/internal/utils"
+)
+
+func (h *Handler) QualityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	report, err := h.services.DataQualityContext(ctx)
+	if err != nil {
+		log.Println("Error building data quality report:", err)
+		utils.ErrorHandler(w, errorStatus(err))
+		return
+	}
+
+	pageData := utils.PageData{
+		Title:           "Data Quality",
+		ContentTemplate: "quality",
+		Data:            report,
+		Outdated:        h.client.Degraded(),
+	}
+
+	if err := utils.RenderTemplate(w, "quality.html", pageData); err != nil {
+		log.Println("Error rendering template:", err)
+		utils.ErrorHandler(w, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) QualityAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	report, err := h.services.DataQualityContext(ctx)
+	if err != nil {
+		log.Println("Error building data quality report:", err)
+		w.WriteHeader(errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
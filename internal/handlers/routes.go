@@ -0,0 +1,21 @@
+package handlers
+
+import "net/http"
+
+func NewMux(h *Handler, staticDir string) *http.ServeMux {
+	mux := http.NewServeMux()
+	fs := http.FileServer(http.Dir(staticDir))
+	mux.Handle("/static/", http.StripPrefix("/static/", fs))
+	mux.HandleFunc("/", h.HomeHandler)
+	mux.HandleFunc("/artist/", h.ArtistHandler)
+	mux.HandleFunc("/search", h.SearchHandler)
+	mux.HandleFunc("/api/search", h.SearchAPIHandler)
+	mux.HandleFunc("/api/suggestions", h.SuggestionsHandler)
+	mux.HandleFunc("/map/", h.GeoHandler)
+	mux.HandleFunc("/quality", h.QualityHandler)
+	mux.HandleFunc("/api/quality", h.QualityAPIHandler)
+	mux.HandleFunc("/changes", h.ChangesHandler)
+	mux.HandleFunc("/api/changes", h.ChangesAPIHandler)
+	mux.HandleFunc("/api/health", h.HealthHandler)
+	return mux
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -10,7 +12,21 @@ import (
 	"groupie-tracker/internal/utils"
 )
 
-func SearchHandler(w http.ResponseWriter, r *http.Request) {
+type SearchData struct {
+	Results    []services.SearchResult
+	Error      string
+	DidYouMean string
+}
+
+type SearchResponse struct {
+	Query      string                  `json:"query"`
+	Results    []services.SearchResult `json:"results"`
+	Error      string                  `json:"error,omitempty"`
+	Position   int                     `json:"position,omitempty"`
+	DidYouMean string                  `json:"didYouMean,omitempty"`
+}
+
+func (h *Handler) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
 		return
@@ -24,16 +40,30 @@ func SearchHandler(w http.ResponseWriter, r *http.Request) {
 		ContentTemplate: "search",
 		SearchQuery:     query,
 		SearchExpanded:  true,
+		Outdated:        h.client.Degraded(),
 	}
 
 	if query != "" {
-		results, err := services.SearchArtists(query)
-		if err != nil {
+		ctx, cancel := h.requestContext(r)
+		defer cancel()
+
+		results, err := h.services.SearchContext(ctx, query)
+		var queryErr *services.QueryError
+		switch {
+		case errors.As(err, &queryErr):
+			w.WriteHeader(http.StatusBadRequest)
+			pageData.Data = SearchData{Error: queryErr.Error()}
+		case err != nil:
 			log.Println("Error searching:", err)
-			utils.ErrorHandler(w, http.StatusInternalServerError)
+			utils.ErrorHandler(w, errorStatus(err))
 			return
+		default:
+			data := SearchData{Results: results}
+			if len(results) == 0 {
+				data.DidYouMean = h.didYouMean(ctx, query)
+			}
+			pageData.Data = data
 		}
-		pageData.Data = results
 	}
 
 	if err := utils.RenderTemplate(w, "search.html", pageData); err != nil {
@@ -42,25 +72,81 @@ func SearchHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func SuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
 		return
 	}
 
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	response := SearchResponse{Query: query, Results: []services.SearchResult{}}
+
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/json")
+	results, err := h.services.SearchContext(ctx, query)
+	var queryErr *services.QueryError
+	switch {
+	case errors.As(err, &queryErr):
+		response.Error = queryErr.Message
+		response.Position = queryErr.Pos + 1
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		log.Println("Error searching:", err)
+		w.WriteHeader(errorStatus(err))
+		response.Error = http.StatusText(errorStatus(err))
+	case len(results) > 0:
+		response.Results = results
+	default:
+		response.DidYouMean = h.didYouMean(ctx, query)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) didYouMean(ctx context.Context, query string) string {
+	if query == "" {
+		return ""
+	}
+	suggestion, err := h.services.DidYouMeanContext(ctx, query)
+	if err != nil {
+		log.Println("Error finding a correction:", err)
+		return ""
+	}
+	return suggestion
+}
+
+func (h *Handler) SuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.ErrorHandler(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query := strings.TrimSpace(params.Get("q"))
+
+	w.Header().Set("Content-Type", "application/json")
+	opts, err := services.ParseSuggestionOptions(params.Get("limit"), params.Get("types"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
 	if query == "" {
 		json.NewEncoder(w).Encode([]services.Suggestion{})
 		return
 	}
 
-	suggestions, err := services.GetSuggestions(query)
+	ctx, cancel := h.requestContext(r)
+	defer cancel()
+
+	suggestions, err := h.services.SuggestContext(ctx, query, opts)
 	if err != nil {
 		log.Println("Error getting suggestions:", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(errorStatus(err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(suggestions)
 }
@@ -0,0 +1,214 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryMin         time.Duration
+	RetryMax         time.Duration
+	MaxBodySize      int64
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+var DefaultConfig = Config{
+	Timeout:          10 * time.Second,
+	MaxRetries:       2,
+	RetryMin:         200 * time.Millisecond,
+	RetryMax:         2 * time.Second,
+	MaxBodySize:      10 << 20,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+var (
+	ErrBodyTooLarge = errors.New("response body too large")
+	ErrCircuitOpen  = errors.New("circuit breaker open")
+)
+
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type Client struct {
+	HTTP   *http.Client
+	config Config
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func New(config Config) *Client {
+	return &Client{
+		HTTP:     &http.Client{Timeout: config.Timeout},
+		config:   config,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+func (c *Client) Get(ctx context.Context, url string, header http.Header) (*Response, error) {
+	b := c.breaker(breakerKey(url))
+	if err := b.allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	var resp *Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		resp, err = c.do(ctx, url, header)
+		if err == nil || !retryable(ctx, err) || attempt >= c.config.MaxRetries {
+			break
+		}
+
+		wait := Backoff(c.config.RetryMin, c.config.RetryMax, attempt+1)
+		log.Printf("Retrying %s in %s (attempt %d): %v", url, wait.Round(time.Millisecond), attempt+1, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			resp, err = nil, ctx.Err()
+		case <-timer.C:
+			continue
+		}
+		break
+	}
+
+	if ctx.Err() != nil {
+		b.release()
+	} else {
+		b.record(err)
+	}
+	return resp, err
+}
+
+func (c *Client) do(ctx context.Context, url string, header http.Header) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	reader := io.Reader(resp.Body)
+	if c.config.MaxBodySize > 0 {
+		reader = io.LimitReader(resp.Body, c.config.MaxBodySize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.MaxBodySize > 0 && int64(len(body)) > c.config.MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	if resp.StatusCode >= 500 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+func breakerKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func Backoff(min, max time.Duration, attempt int) time.Duration {
+	wait := min
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (c *Client) breaker(key string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[key]
+	if !ok {
+		b = &breaker{
+			key:       key,
+			threshold: c.config.BreakerThreshold,
+			cooldown:  c.config.BreakerCooldown,
+			state:     StateClosed,
+		}
+		c.breakers[key] = b
+	}
+	return b
+}
+
+func (c *Client) Breakers() []BreakerStatus {
+	c.mu.Lock()
+	breakers := make([]*breaker, 0, len(c.breakers))
+	for _, b := range c.breakers {
+		breakers = append(breakers, b)
+	}
+	c.mu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(breakers))
+	for _, b := range breakers {
+		statuses = append(statuses, b.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Endpoint < statuses[j].Endpoint
+	})
+	return statuses
+}
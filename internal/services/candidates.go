@@ -0,0 +1,110 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"groupie-tracker/internal/textindex"
+)
+
+type candidateSet map[int]bool
+
+func (s candidateSet) addEntries(index *textindex.Index, entries []int) {
+	for _, i := range entries {
+		for _, id := range index.Entry(i).IDs {
+			s[id] = true
+		}
+	}
+}
+
+func (n andNode) candidates(index *textindex.Index) (candidateSet, bool) {
+	var result candidateSet
+	bounded := false
+	for _, child := range n {
+		ids, ok := child.candidates(index)
+		if !ok {
+			continue
+		}
+		if !bounded {
+			result, bounded = ids, true
+			continue
+		}
+		for id := range result {
+			if !ids[id] {
+				delete(result, id)
+			}
+		}
+	}
+	return result, bounded
+}
+
+func (n orNode) candidates(index *textindex.Index) (candidateSet, bool) {
+	result := make(candidateSet)
+	for _, child := range n {
+		ids, ok := child.candidates(index)
+		if !ok {
+			return nil, false
+		}
+		for id := range ids {
+			result[id] = true
+		}
+	}
+	return result, true
+}
+
+func (n notNode) candidates(index *textindex.Index) (candidateSet, bool) {
+	return nil, false
+}
+
+func (n termNode) candidates(index *textindex.Index) (candidateSet, bool) {
+	ids := make(candidateSet)
+
+	switch n.field {
+	case "":
+		ids.addEntries(index, index.Search(n.value))
+		if n.dated {
+			ids.addEntries(index, index.Search(n.date.String(), FieldDate))
+		}
+	case FieldName, FieldMember, FieldLocation:
+		ids.addEntries(index, index.Search(n.value, n.field))
+	case FieldAlbum:
+		if n.ranged {
+			return nil, false
+		}
+		ids.addEntries(index, index.Search(n.value, n.field))
+	case FieldDate:
+		if n.dated {
+			ids.addEntries(index, index.Search(n.date.String(), n.field))
+			break
+		}
+		for _, word := range index.Words(FieldDate) {
+			if year, err := strconv.Atoi(word); err == nil && len(word) == 4 && year >= n.min && year <= n.max {
+				ids.addEntries(index, index.WordEntries(FieldDate, word))
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	switch n.field {
+	case "":
+		return ids, n.fuzzyCandidates(index, ids, FieldName, FieldMember)
+	case FieldName, FieldMember, FieldLocation:
+		return ids, n.fuzzyCandidates(index, ids, n.field)
+	}
+	return ids, true
+}
+
+func (n termNode) fuzzyCandidates(index *textindex.Index, ids candidateSet, fields ...string) bool {
+	if strings.Contains(n.value, " ") {
+		return FuzzyThreshold <= 0
+	}
+	for _, field := range fields {
+		for _, word := range index.Words(field) {
+			if _, ok := fuzzyDistance(word, n.value, FuzzyThreshold); ok {
+				ids.addEntries(index, index.WordEntries(field, word))
+			}
+		}
+	}
+	return true
+}
@@ -1,9 +1,7 @@
 package services
 
 import (
-	"strconv"
-	"strings"
-
+	"context"
 	"groupie-tracker/internal/api"
 )
 
@@ -17,16 +15,34 @@ type FilterParams struct {
 	Locations       []string
 }
 
-func ApplyFilters(params FilterParams) ([]api.Artist, error) {
-	data, err := api.FetchAPI()
+func (s *Service) ApplyFilters(params FilterParams) ([]api.Artist, error) {
+	return s.ApplyFiltersContext(context.Background(), params)
+}
+
+func (s *Service) ApplyFiltersContext(ctx context.Context, params FilterParams) ([]api.Artist, error) {
+	catalog, err := s.client.CatalogContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	created := catalog.CreatedBetween(params.CreationDateMin, params.CreationDateMax)
+	firstAlbum := catalog.FirstAlbumBetween(params.FirstAlbumMin, params.FirstAlbumMax)
+
+	var playedAt map[int]bool
+	if len(params.Locations) > 0 {
+		playedAt = catalog.PlayedAt(params.Locations)
+	}
+
 	var results []api.Artist
 
-	for _, artist := range data.Artists {
-		if matchesFilters(artist, params, data) {
+	for _, artist := range catalog.Artists() {
+		if !created[artist.ID] || !firstAlbum[artist.ID] {
+			continue
+		}
+		if playedAt != nil && !playedAt[artist.ID] {
+			continue
+		}
+		if matchesFilters(artist, params) {
 			results = append(results, artist)
 		}
 	}
@@ -34,55 +50,7 @@ func ApplyFilters(params FilterParams) ([]api.Artist, error) {
 	return results, nil
 }
 
-func matchesFilters(artist api.Artist, params FilterParams, data *api.APIData) bool {
-	if artist.CreationDate < params.CreationDateMin || artist.CreationDate > params.CreationDateMax {
-		return false
-	}
-
-	albumYear := extractYear(artist.FirstAlbum)
-	if albumYear < params.FirstAlbumMin || albumYear > params.FirstAlbumMax {
-		return false
-	}
-
+func matchesFilters(artist api.Artist, params FilterParams) bool {
 	memberCount := len(artist.Members)
-	if memberCount < params.MembersMin || memberCount > params.MembersMax {
-		return false
-	}
-
-	if len(params.Locations) > 0 {
-		if !hasLocationMatch(artist.ID, params.Locations, data) {
-			return false
-		}
-	}
-
-	return true
-}
-
-func extractYear(dateStr string) int {
-	parts := strings.Split(dateStr, "-")
-	if len(parts) == 3 {
-		year, err := strconv.Atoi(parts[2])
-		if err == nil {
-			return year
-		}
-	}
-	return 0
-}
-
-func hasLocationMatch(artistID int, locations []string, data *api.APIData) bool {
-	for _, relation := range data.Relations.Index {
-		if relation.ID == artistID {
-			for location := range relation.DatesLocations {
-				locationLower := strings.ToLower(location)
-				for _, filterLoc := range locations {
-					filterLower := strings.ToLower(filterLoc)
-					if strings.Contains(locationLower, filterLower) {
-						return true
-					}
-				}
-			}
-			break
-		}
-	}
-	return false
+	return memberCount >= params.MembersMin && memberCount <= params.MembersMax
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"groupie-tracker/internal/textindex"
+	"groupie-tracker/internal/textnorm"
+)
+
+var (
+	FuzzyThreshold      = 1.0 / 3
+	CorrectionThreshold = 0.5
+)
+
+const (
+	scoreNameFuzzy     = 25
+	scoreMemberFuzzy   = 15
+	scoreLocationFuzzy = 10
+	scoreFuzzyPenalty  = 5
+)
+
+func maxEdits(term string, threshold float64) int {
+	n := utf8.RuneCountInString(term)
+	if n < 3 || isNumeric(term) {
+		return 0
+	}
+	return int(float64(n) * threshold)
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func fuzzyDistance(a, b string, threshold float64) (int, bool) {
+	shorter := a
+	if utf8.RuneCountInString(b) < utf8.RuneCountInString(a) {
+		shorter = b
+	}
+	limit := maxEdits(shorter, threshold)
+	if limit == 0 {
+		return 0, false
+	}
+	d := editDistance(a, b, limit)
+	return d, d <= limit
+}
+
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			best = min(best, curr[j])
+		}
+		if best > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+func matchFuzzy(field, value, query string) (FieldMatch, bool) {
+	folded, offsets := textnorm.FoldOffsets(value)
+	best := -1
+	var spans []Span
+
+	if strings.Contains(query, " ") {
+		if d, ok := fuzzyDistance(folded, query, FuzzyThreshold); ok {
+			best = d
+			spans = []Span{{0, len(value)}}
+		}
+	} else {
+		for _, token := range textindex.Tokenize(folded) {
+			if d, ok := fuzzyDistance(token.Text, query, FuzzyThreshold); ok && (best < 0 || d < best) {
+				best = d
+				spans = []Span{{offsets[token.Start].Start, offsets[token.End-1].End}}
+			}
+		}
+	}
+	if best < 0 {
+		return FieldMatch{}, false
+	}
+
+	m := FieldMatch{Field: field, Value: value, Spans: spans, Fuzzy: true}
+	switch field {
+	case FieldName:
+		m.Score = scoreNameFuzzy
+	case FieldMember:
+		m.Score = scoreMemberFuzzy
+	default:
+		m.Score = scoreLocationFuzzy
+	}
+	m.Score -= best * scoreFuzzyPenalty
+	return m, true
+}
+
+func (s *Service) DidYouMean(query string) (string, error) {
+	return s.DidYouMeanContext(context.Background(), query)
+}
+
+func (s *Service) DidYouMeanContext(ctx context.Context, query string) (string, error) {
+	parsed, err := ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	catalog, err := s.client.CatalogContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	vocabulary := buildVocabulary(catalog.Index())
+	corrected := parsed.correct(vocabulary)
+	if corrected == "" {
+		return "", nil
+	}
+
+	results, err := s.SearchContext(ctx, corrected)
+	if err != nil || len(results) == 0 {
+		return "", err
+	}
+	return corrected, nil
+}
+
+func buildVocabulary(index *textindex.Index) map[string][]string {
+	vocabulary := make(map[string][]string)
+	for _, field := range []string{FieldName, FieldMember, FieldLocation} {
+		words := append([]string(nil), index.Words(field)...)
+		sort.Strings(words)
+		vocabulary[field] = words
+		vocabulary[""] = append(vocabulary[""], words...)
+	}
+	return vocabulary
+}
+
+func (q *Query) correct(vocabulary map[string][]string) string {
+	changed := false
+	parts := make([]string, 0, len(q.tokens))
+
+	for _, tok := range q.tokens {
+		switch tok.kind {
+		case tokEOF:
+			continue
+		case tokTerm:
+		default:
+			parts = append(parts, tok.text)
+			continue
+		}
+
+		words, ok := vocabulary[tok.field]
+		text := tok.text
+		if ok {
+			fixed := make([]string, 0, 2)
+			for _, w := range strings.Fields(textnorm.Fold(text)) {
+				if c := closestWord(w, words); c != w {
+					changed = true
+					w = c
+				}
+				fixed = append(fixed, w)
+			}
+			text = strings.Join(fixed, " ")
+		}
+
+		if strings.Contains(text, " ") {
+			text = `"` + text + `"`
+		}
+		if tok.field != "" {
+			text = tok.field + ":" + text
+		}
+		parts = append(parts, text)
+	}
+
+	if !changed {
+		return ""
+	}
+	return strings.ReplaceAll(strings.Join(parts, " "), "- ", "-")
+}
+
+func closestWord(w string, words []string) string {
+	best, bestDistance := w, -1
+	for _, candidate := range words {
+		if strings.HasPrefix(candidate, w) {
+			return w
+		}
+		if d, ok := fuzzyDistance(w, candidate, CorrectionThreshold); ok && (bestDistance < 0 || d < bestDistance) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
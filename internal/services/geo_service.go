@@ -1,22 +1,23 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 	"sync"
 	"time"
 
 	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/httpclient"
 )
 
 type GeoLocation struct {
 	Name      string
 	Latitude  float64
 	Longitude float64
-	Dates     []string
+	Dates     []time.Time
 }
 
 type NominatimResponse []struct {
@@ -24,33 +25,59 @@ type NominatimResponse []struct {
 	Lon string `json:"lon"`
 }
 
+var GeocodeTimeout = 5 * time.Second
+
+var geoClient = httpclient.New(httpclient.Config{
+	MaxRetries:       1,
+	RetryMin:         500 * time.Millisecond,
+	RetryMax:         time.Second,
+	MaxBodySize:      1 << 20,
+	BreakerThreshold: 5,
+	BreakerCooldown:  time.Minute,
+})
+
 var (
 	geoCache      = make(map[string][2]float64)
 	geoCacheMutex sync.RWMutex
 )
 
-func GeocodeLocations(relation *api.Relation) ([]GeoLocation, error) {
+func UseGeocodeTransport(transport http.RoundTripper) {
+	geoClient.HTTP.Transport = transport
+}
+
+func GeocoderStatus() []httpclient.BreakerStatus {
+	return geoClient.Breakers()
+}
+
+func GeocodeLocations(tour []api.PlaceConcerts) ([]GeoLocation, error) {
+	return GeocodeLocationsContext(context.Background(), tour)
+}
+
+func GeocodeLocationsContext(ctx context.Context, tour []api.PlaceConcerts) ([]GeoLocation, error) {
 	var geoLocations []GeoLocation
 	count := 0
 	maxLocations := 5
 
-	for location, dates := range relation.DatesLocations {
+	for _, stop := range tour {
 		if count >= maxLocations {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return geoLocations, err
+		}
 
-		formattedLocation := formatLocation(location)
+		name := stop.Location.String()
 
-		lat, lon, err := geocode(formattedLocation)
+		lat, lon, err := geocode(ctx, name)
 		if err != nil {
 			continue
 		}
 
 		geoLocations = append(geoLocations, GeoLocation{
-			Name:      formattedLocation,
+			Name:      name,
 			Latitude:  lat,
 			Longitude: lon,
-			Dates:     dates,
+			Dates:     stop.Dates,
 		})
 		count++
 	}
@@ -58,16 +85,7 @@ func GeocodeLocations(relation *api.Relation) ([]GeoLocation, error) {
 	return geoLocations, nil
 }
 
-func formatLocation(location string) string {
-	parts := strings.Split(location, "-")
-	for i, part := range parts {
-		part = strings.ReplaceAll(part, "_", " ")
-		parts[i] = strings.Title(strings.ToLower(part))
-	}
-	return strings.Join(parts, ", ")
-}
-
-func geocode(address string) (float64, float64, error) {
+func geocode(ctx context.Context, address string) (float64, float64, error) {
 	geoCacheMutex.RLock()
 	if coords, ok := geoCache[address]; ok {
 		geoCacheMutex.RUnlock()
@@ -83,26 +101,23 @@ func geocode(address string) (float64, float64, error) {
 
 	reqURL := baseURL + "?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return 0, 0, err
-	}
+	ctx, cancel := context.WithTimeout(ctx, GeocodeTimeout)
+	defer cancel()
 
-	req.Header.Set("User-Agent", "Groupie-Tracker/1.0")
+	header := http.Header{}
+	header.Set("User-Agent", "Groupie-Tracker/1.0")
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := geoClient.Get(ctx, reqURL, header)
 	if err != nil {
 		return 0, 0, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		return 0, 0, fmt.Errorf("geocoding failed: status %d", resp.StatusCode)
 	}
 
 	var result NominatimResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
 		return 0, 0, err
 	}
 
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"groupie-tracker/internal/api"
+)
+
+type FieldAnomalies struct {
+	Field     string        `json:"field"`
+	Anomalies []api.Anomaly `json:"anomalies"`
+}
+
+type ArtistAnomalies struct {
+	ArtistID int              `json:"artistId"`
+	Artist   string           `json:"artist"`
+	Fields   []FieldAnomalies `json:"fields"`
+}
+
+type QualityReport struct {
+	Total        int               `json:"total"`
+	Unrepaired   int               `json:"unrepaired"`
+	Artists      []ArtistAnomalies `json:"artists"`
+	StalePatches []api.StalePatch  `json:"stalePatches"`
+}
+
+func (s *Service) DataQuality() (*QualityReport, error) {
+	return s.DataQualityContext(context.Background())
+}
+
+func (s *Service) DataQualityContext(ctx context.Context) (*QualityReport, error) {
+	catalog, err := s.client.CatalogContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := BuildQualityReport(catalog.Anomalies())
+	if stale := catalog.StalePatches(); stale != nil {
+		report.StalePatches = stale
+	}
+	return report, nil
+}
+
+func BuildQualityReport(anomalies []api.Anomaly) *QualityReport {
+	sorted := make([]api.Anomaly, len(anomalies))
+	copy(sorted, anomalies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.ArtistID != b.ArtistID {
+			return a.ArtistID < b.ArtistID
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Value < b.Value
+	})
+
+	report := &QualityReport{
+		Total:        len(sorted),
+		Artists:      []ArtistAnomalies{},
+		StalePatches: []api.StalePatch{},
+	}
+	for _, anomaly := range sorted {
+		if !anomaly.Repaired {
+			report.Unrepaired++
+		}
+
+		n := len(report.Artists)
+		if n == 0 || report.Artists[n-1].ArtistID != anomaly.ArtistID {
+			report.Artists = append(report.Artists, ArtistAnomalies{
+				ArtistID: anomaly.ArtistID,
+				Artist:   anomaly.Artist,
+			})
+			n++
+		}
+		artist := &report.Artists[n-1]
+
+		m := len(artist.Fields)
+		if m == 0 || artist.Fields[m-1].Field != anomaly.Field {
+			artist.Fields = append(artist.Fields, FieldAnomalies{Field: anomaly.Field})
+			m++
+		}
+		artist.Fields[m-1].Anomalies = append(artist.Fields[m-1].Anomalies, anomaly)
+	}
+
+	return report
+}
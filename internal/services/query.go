@@ -0,0 +1,577 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/textindex"
+	"groupie-tracker/internal/textnorm"
+)
+
+const (
+	FieldName     = api.IndexName
+	FieldMember   = api.IndexMember
+	FieldLocation = api.IndexLocation
+	FieldCreated  = api.IndexCreated
+	FieldAlbum    = api.IndexAlbum
+	FieldDate     = api.IndexDate
+)
+
+var queryFields = map[string]bool{
+	FieldName:     true,
+	FieldMember:   true,
+	FieldLocation: true,
+	FieldCreated:  true,
+	FieldAlbum:    true,
+	FieldDate:     true,
+}
+
+type QueryError struct {
+	Query   string
+	Pos     int
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos+1)
+}
+
+type Query struct {
+	Raw    string
+	root   queryNode
+	tokens []token
+}
+
+type queryNode interface {
+	eval(artist *api.Artist, catalog *api.Catalog) evaluation
+	candidates(index *textindex.Index) (candidateSet, bool)
+}
+
+type evaluation struct {
+	ok      bool
+	score   int
+	matches []FieldMatch
+}
+
+type andNode []queryNode
+
+func (n andNode) eval(artist *api.Artist, catalog *api.Catalog) evaluation {
+	result := evaluation{ok: true}
+	for _, child := range n {
+		e := child.eval(artist, catalog)
+		if !e.ok {
+			return evaluation{}
+		}
+		result.score += e.score
+		result.matches = append(result.matches, e.matches...)
+	}
+	return result
+}
+
+type orNode []queryNode
+
+func (n orNode) eval(artist *api.Artist, catalog *api.Catalog) evaluation {
+	var result evaluation
+	for _, child := range n {
+		e := child.eval(artist, catalog)
+		if !e.ok {
+			continue
+		}
+		result.ok = true
+		if e.score > result.score {
+			result.score = e.score
+		}
+		result.matches = append(result.matches, e.matches...)
+	}
+	return result
+}
+
+type notNode struct {
+	node queryNode
+}
+
+func (n notNode) eval(artist *api.Artist, catalog *api.Catalog) evaluation {
+	return evaluation{ok: !n.node.eval(artist, catalog).ok}
+}
+
+type termNode struct {
+	field    string
+	value    string
+	ranged   bool
+	min, max int
+	date     datePattern
+	dated    bool
+}
+
+func (n termNode) eval(artist *api.Artist, catalog *api.Catalog) evaluation {
+	var matches []FieldMatch
+	add := func(m FieldMatch, ok bool) {
+		if ok {
+			matches = append(matches, m)
+		}
+	}
+
+	if n.field == "" || n.field == FieldName {
+		add(matchTextOrFuzzy(FieldName, artist.Name, n.value))
+	}
+	if n.field == "" || n.field == FieldMember {
+		for _, member := range artist.Members {
+			add(matchTextOrFuzzy(FieldMember, member, n.value))
+		}
+	}
+	if n.field == "" || n.field == FieldLocation {
+		for _, stop := range catalog.Tour(artist.ID) {
+			if m, ok := matchPlace(stop.Location, n.value); ok {
+				add(m, true)
+			} else if n.field == FieldLocation {
+				add(matchFuzzy(FieldLocation, stop.Location.String(), n.value))
+			}
+		}
+	}
+	if (n.field == "" || n.field == FieldDate) && (n.dated || n.ranged) {
+		for _, concert := range catalog.ConcertsByArtist(artist.ID) {
+			year := concert.Date.Year()
+			switch {
+			case n.dated && n.date.matches(concert.Date):
+				matches = append(matches, concertMatch(concert, n.date))
+			case n.ranged && n.field == FieldDate && year >= n.min && year <= n.max:
+				matches = append(matches, concertMatch(concert, datePattern{year: year}))
+			}
+		}
+	}
+	if n.field == "" || n.field == FieldCreated {
+		year := strconv.Itoa(artist.CreationDate)
+		if n.ranged {
+			add(FieldMatch{Field: FieldCreated, Value: year, Spans: []Span{{0, len(year)}}, Score: scoreYear},
+				artist.CreationDate >= n.min && artist.CreationDate <= n.max)
+		} else if m, ok := matchText(FieldCreated, year, n.value); ok {
+			m.Score = scoreYear
+			add(m, true)
+		}
+	}
+	if n.field == "" || n.field == FieldAlbum {
+		if n.ranged {
+			year := artist.FirstAlbumDate.Year()
+			add(FieldMatch{Field: FieldAlbum, Value: artist.FirstAlbum, Score: scoreYear},
+				!artist.FirstAlbumDate.IsZero() && year >= n.min && year <= n.max)
+		} else if m, ok := matchText(FieldAlbum, artist.FirstAlbum, n.value); ok {
+			m.Score = scoreYear
+			add(m, true)
+		}
+	}
+
+	result := evaluation{ok: len(matches) > 0, matches: matches}
+	for _, m := range matches {
+		if m.Score > result.score {
+			result.score = m.Score
+		}
+	}
+	return result
+}
+
+func matchPlace(place api.Place, query string) (FieldMatch, bool) {
+	if query == "" || !strings.Contains(place.SearchText(), query) {
+		return FieldMatch{}, false
+	}
+	name := place.String()
+	return FieldMatch{Field: FieldLocation, Value: name, Spans: findSpans(name, query), Score: scoreLocation}, true
+}
+
+func concertMatch(concert api.Concert, p datePattern) FieldMatch {
+	date := concert.Date.Format("02-01-2006")
+	span := Span{len(date) - len("2006"), len(date)}
+	switch {
+	case p.day != 0:
+		span.Start = 0
+	case p.month != 0:
+		span.Start = len(date) - len("01-2006")
+	}
+	return FieldMatch{
+		Field: FieldDate,
+		Value: date + " in " + concert.Location.String(),
+		Spans: []Span{span},
+		Score: scoreYear,
+	}
+}
+
+func (q *Query) Match(artist *api.Artist, catalog *api.Catalog) bool {
+	return q.root.eval(artist, catalog).ok
+}
+
+func (q *Query) Evaluate(artist *api.Artist, catalog *api.Catalog) (SearchResult, bool) {
+	e := q.root.eval(artist, catalog)
+	if !e.ok {
+		return SearchResult{}, false
+	}
+	return SearchResult{Artist: *artist, Score: e.score, Matches: mergeMatches(e.matches)}, true
+}
+
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{query: query, tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return &Query{Raw: query, root: root, tokens: tokens}, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokTerm
+	tokOr
+	tokAnd
+	tokNot
+	tokMinus
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	field string
+	pos   int
+}
+
+func lexQuery(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	i := 0
+
+	fail := func(pos int, format string, args ...interface{}) error {
+		return &QueryError{Query: query, Pos: pos, Message: fmt.Sprintf(format, args...)}
+	}
+
+	readPhrase := func(start int) (string, error) {
+		end := start + 1
+		for end < len(runes) && runes[end] != '"' {
+			end++
+		}
+		if end == len(runes) {
+			return "", fail(start, "unterminated quote")
+		}
+		i = end + 1
+		return string(runes[start+1 : end]), nil
+	}
+
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: i})
+			i++
+		case r == '"':
+			start := i
+			phrase, err := readPhrase(i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokTerm, text: phrase, pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			word := string(runes[start:i])
+
+			switch word {
+			case "OR":
+				tokens = append(tokens, token{kind: tokOr, text: word, pos: start})
+				continue
+			case "AND":
+				tokens = append(tokens, token{kind: tokAnd, text: word, pos: start})
+				continue
+			case "NOT":
+				tokens = append(tokens, token{kind: tokNot, text: word, pos: start})
+				continue
+			}
+
+			tok := token{kind: tokTerm, text: word, pos: start}
+			if field, value, ok := strings.Cut(word, ":"); ok && isFieldName(field) {
+				if !queryFields[strings.ToLower(field)] {
+					return nil, fail(start, "unknown field %q", field)
+				}
+				if value == "" && i < len(runes) && runes[i] == '"' {
+					phrase, err := readPhrase(i)
+					if err != nil {
+						return nil, err
+					}
+					value = phrase
+				}
+				if value == "" {
+					return nil, fail(start, "missing value for %s", field)
+				}
+				tok.field, tok.text = strings.ToLower(field), value
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes)}), nil
+}
+
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type queryParser struct {
+	query  string
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) errorf(tok token, format string, args ...interface{}) error {
+	return &QueryError{Query: p.query, Pos: tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := orNode{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := andNode{first}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokTerm, tokMinus, tokNot, tokLParen:
+		default:
+			if len(nodes) == 1 {
+				return first, nil
+			}
+			return nodes, nil
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	switch p.peek().kind {
+	case tokMinus, tokNot:
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, p.errorf(p.peek(), "empty parentheses")
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(tok, "missing closing parenthesis")
+		}
+		return node, nil
+	case tokTerm:
+		return p.parseTerm(tok)
+	case tokEOF:
+		return nil, p.errorf(tok, "expected a search term")
+	}
+	return nil, p.errorf(tok, "expected a search term before %q", tok.text)
+}
+
+func (p *queryParser) parseTerm(tok token) (queryNode, error) {
+	raw := strings.ToLower(strings.TrimSpace(tok.text))
+	if raw == "" {
+		return nil, p.errorf(tok, "empty phrase")
+	}
+	term := termNode{field: tok.field, value: textnorm.Fold(raw)}
+
+	if term.field == "" || term.field == FieldDate {
+		term.date, term.dated = parseDatePattern(raw)
+	}
+
+	switch term.field {
+	case FieldDate:
+		if !term.dated {
+			min, max, ok := parseYearRange(raw)
+			if !ok {
+				return nil, p.errorf(tok, "date expects a date like 2019, 08-2019 or 14-12-1973, got %q", tok.text)
+			}
+			term.ranged, term.min, term.max = true, min, max
+		}
+	case FieldCreated, FieldAlbum:
+		min, max, ok := parseYearRange(raw)
+		if ok {
+			term.ranged, term.min, term.max = true, min, max
+		} else if term.field == FieldCreated {
+			return nil, p.errorf(tok, "%s expects a year or range like 1970..1980, got %q", term.field, tok.text)
+		}
+	}
+	if term.value == "" && !term.dated && !term.ranged {
+		return nil, p.errorf(tok, "nothing to search for in %q", tok.text)
+	}
+	return term, nil
+}
+
+type datePattern struct {
+	day, month, year int
+}
+
+func parseDatePattern(s string) (datePattern, bool) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '/' || r == '.' })
+	if len(parts) == 0 || len(parts) > 3 || len(parts[len(parts)-1]) != 4 {
+		return datePattern{}, false
+	}
+
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n <= 0 {
+			return datePattern{}, false
+		}
+		numbers[i] = n
+	}
+
+	var p datePattern
+	switch len(numbers) {
+	case 3:
+		p.day, p.month, p.year = numbers[0], numbers[1], numbers[2]
+	case 2:
+		p.month, p.year = numbers[0], numbers[1]
+	default:
+		p.year = numbers[0]
+	}
+	if p.day > 31 || p.month > 12 {
+		return datePattern{}, false
+	}
+	return p, true
+}
+
+func (p datePattern) String() string {
+	switch {
+	case p.day != 0:
+		return fmt.Sprintf("%02d-%02d-%04d", p.day, p.month, p.year)
+	case p.month != 0:
+		return fmt.Sprintf("%02d-%04d", p.month, p.year)
+	}
+	return fmt.Sprintf("%04d", p.year)
+}
+
+func (p datePattern) matches(t time.Time) bool {
+	if t.IsZero() || t.Year() != p.year {
+		return false
+	}
+	if p.month != 0 && int(t.Month()) != p.month {
+		return false
+	}
+	return p.day == 0 || t.Day() == p.day
+}
+
+func parseYearRange(s string) (int, int, bool) {
+	const lowest, highest = 0, 9999
+
+	year := func(s string) (int, bool) {
+		n, err := strconv.Atoi(s)
+		return n, err == nil && n >= lowest && n <= highest
+	}
+
+	switch {
+	case strings.HasPrefix(s, ">="):
+		n, ok := year(s[2:])
+		return n, highest, ok
+	case strings.HasPrefix(s, "<="):
+		n, ok := year(s[2:])
+		return lowest, n, ok
+	case strings.HasPrefix(s, ">"):
+		n, ok := year(s[1:])
+		return n + 1, highest, ok
+	case strings.HasPrefix(s, "<"):
+		n, ok := year(s[1:])
+		return lowest, n - 1, ok
+	}
+
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		min, max := lowest, highest
+		var okMin, okMax = true, true
+		if from != "" {
+			min, okMin = year(from)
+		}
+		if to != "" {
+			max, okMax = year(to)
+		}
+		return min, max, okMin && okMax && (from != "" || to != "") && min <= max
+	}
+
+	n, ok := year(s)
+	return n, n, ok
+}
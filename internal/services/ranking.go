@@ -0,0 +1,218 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/textnorm"
+)
+
+const (
+	scoreNameExact        = 100
+	scoreNamePrefix       = 80
+	scoreNameWordPrefix   = 60
+	scoreNameInfix        = 50
+	scoreMemberExact      = 45
+	scoreMemberWordPrefix = 40
+	scoreMemberInfix      = 30
+	scoreLocation         = 20
+	scoreYear             = 10
+)
+
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type FieldMatch struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+	Spans []Span `json:"spans,omitempty"`
+	Score int    `json:"score"`
+	Fuzzy bool   `json:"fuzzy,omitempty"`
+}
+
+type TextPart struct {
+	Text  string
+	Match bool
+}
+
+type SearchResult struct {
+	Artist  api.Artist   `json:"artist"`
+	Score   int          `json:"score"`
+	Matches []FieldMatch `json:"matches"`
+}
+
+var fieldLabels = map[string]string{
+	FieldName:     "name",
+	FieldMember:   "member",
+	FieldLocation: "location",
+	FieldCreated:  "creation date",
+	FieldAlbum:    "first album",
+	FieldDate:     "concert date",
+}
+
+func (m FieldMatch) Label() string {
+	return fieldLabels[m.Field]
+}
+
+func (m FieldMatch) Parts() []TextPart {
+	var parts []TextPart
+	pos := 0
+	for _, span := range m.Spans {
+		if span.Start > pos {
+			parts = append(parts, TextPart{Text: m.Value[pos:span.Start]})
+		}
+		parts = append(parts, TextPart{Text: m.Value[span.Start:span.End], Match: true})
+		pos = span.End
+	}
+	if pos < len(m.Value) {
+		parts = append(parts, TextPart{Text: m.Value[pos:]})
+	}
+	return parts
+}
+
+func (r SearchResult) NameMatch() *FieldMatch {
+	for i := range r.Matches {
+		if r.Matches[i].Field == FieldName {
+			return &r.Matches[i]
+		}
+	}
+	return nil
+}
+
+func (r SearchResult) OtherMatches() []FieldMatch {
+	var others []FieldMatch
+	for _, m := range r.Matches {
+		if m.Field != FieldName {
+			others = append(others, m)
+		}
+	}
+	return others
+}
+
+func matchText(field, value, query string) (FieldMatch, bool) {
+	folded := textnorm.Fold(value)
+	if query == "" || !strings.Contains(folded, query) {
+		return FieldMatch{}, false
+	}
+
+	spans := findSpans(value, query)
+	m := FieldMatch{Field: field, Value: value, Spans: spans}
+	exact := folded == query
+	prefix := strings.HasPrefix(folded, query)
+	wordPrefix := false
+	for _, span := range spans {
+		if atWordStart(value, span.Start) {
+			wordPrefix = true
+		}
+	}
+
+	switch field {
+	case FieldName:
+		m.Score = rankScore(exact, prefix, wordPrefix, scoreNameExact, scoreNamePrefix, scoreNameWordPrefix, scoreNameInfix)
+	case FieldMember:
+		m.Score = rankScore(exact, prefix, wordPrefix, scoreMemberExact, scoreMemberWordPrefix, scoreMemberWordPrefix, scoreMemberInfix)
+	}
+	return m, true
+}
+
+func matchTextOrFuzzy(field, value, query string) (FieldMatch, bool) {
+	if m, ok := matchText(field, value, query); ok {
+		return m, true
+	}
+	return matchFuzzy(field, value, query)
+}
+
+func rankScore(exact, prefix, wordPrefix bool, scores ...int) int {
+	switch {
+	case exact:
+		return scores[0]
+	case prefix:
+		return scores[1]
+	case wordPrefix:
+		return scores[2]
+	}
+	return scores[3]
+}
+
+func findSpans(value, query string) []Span {
+	if query == "" {
+		return nil
+	}
+	folded, offsets := textnorm.FoldOffsets(value)
+
+	var spans []Span
+	for offset := 0; offset < len(folded); {
+		i := strings.Index(folded[offset:], query)
+		if i < 0 {
+			break
+		}
+		start, end := offset+i, offset+i+len(query)
+		spans = append(spans, Span{Start: offsets[start].Start, End: offsets[end-1].End})
+		offset = end
+	}
+	return spans
+}
+
+func atWordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func mergeMatches(matches []FieldMatch) []FieldMatch {
+	var merged []FieldMatch
+	index := make(map[string]int)
+	for _, m := range matches {
+		key := m.Field + "\x00" + m.Value
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, m)
+			continue
+		}
+		if m.Score > merged[i].Score {
+			merged[i].Score = m.Score
+		}
+		merged[i].Spans = append(merged[i].Spans, m.Spans...)
+	}
+
+	for i := range merged {
+		merged[i].Spans = normalizeSpans(merged[i].Spans)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	return merged
+}
+
+func normalizeSpans(spans []Span) []Span {
+	if len(spans) < 2 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	result := []Span{spans[0]}
+	for _, span := range spans[1:] {
+		last := &result[len(result)-1]
+		if span.Start <= last.End {
+			if span.End > last.End {
+				last.End = span.End
+			}
+			continue
+		}
+		result = append(result, span)
+	}
+	return result
+}
+
+func rankResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
@@ -1,123 +1,56 @@
 package services
 
 import (
-	"strconv"
-	"strings"
+	"context"
 
 	"groupie-tracker/internal/api"
 )
 
-type Suggestion struct {
-	Text string `json:"text"`
-	Type string `json:"type"`
+func (s *Service) SearchArtists(query string) ([]api.Artist, error) {
+	return s.SearchArtistsContext(context.Background(), query)
 }
 
-func SearchArtists(query string) ([]api.Artist, error) {
-	data, err := api.FetchAPI()
+func (s *Service) SearchArtistsContext(ctx context.Context, query string) ([]api.Artist, error) {
+	results, err := s.SearchContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	query = strings.ToLower(query)
-	var results []api.Artist
-
-	for _, artist := range data.Artists {
-		if matchesArtist(artist, query) {
-			results = append(results, artist)
-		}
+	var artists []api.Artist
+	for _, result := range results {
+		artists = append(artists, result.Artist)
 	}
-
-	return results, nil
+	return artists, nil
 }
 
-func matchesArtist(artist api.Artist, query string) bool {
-	if strings.Contains(strings.ToLower(artist.Name), query) {
-		return true
-	}
-
-	for _, member := range artist.Members {
-		if strings.Contains(strings.ToLower(member), query) {
-			return true
-		}
-	}
-
-	if strings.Contains(strconv.Itoa(artist.CreationDate), query) {
-		return true
-	}
-
-	if strings.Contains(strings.ToLower(artist.FirstAlbum), query) {
-		return true
-	}
-
-	return false
+func (s *Service) Search(query string) ([]SearchResult, error) {
+	return s.SearchContext(context.Background(), query)
 }
 
-func GetSuggestions(query string) ([]Suggestion, error) {
-	if query == "" {
-		return []Suggestion{}, nil
+func (s *Service) SearchContext(ctx context.Context, query string) ([]SearchResult, error) {
+	parsed, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := api.FetchAPI()
+	catalog, err := s.client.CatalogContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	query = strings.ToLower(query)
-	suggestions := []Suggestion{}
-	seen := make(map[string]bool)
+	ids, bounded := parsed.root.candidates(catalog.Index())
 
-	for _, artist := range data.Artists {
-		if strings.Contains(strings.ToLower(artist.Name), query) {
-			key := artist.Name + "-artist"
-			if !seen[key] {
-				suggestions = append(suggestions, Suggestion{
-					Text: artist.Name,
-					Type: "artist/band",
-				})
-				seen[key] = true
-			}
+	var results []SearchResult
+	artists := catalog.Artists()
+	for i := range artists {
+		if bounded && !ids[artists[i].ID] {
+			continue
 		}
-
-		for _, member := range artist.Members {
-			if strings.Contains(strings.ToLower(member), query) {
-				key := member + "-member"
-				if !seen[key] {
-					suggestions = append(suggestions, Suggestion{
-						Text: member,
-						Type: "member",
-					})
-					seen[key] = true
-				}
-			}
-		}
-
-		creationStr := strconv.Itoa(artist.CreationDate)
-		if strings.Contains(creationStr, query) {
-			key := creationStr + "-creation"
-			if !seen[key] {
-				suggestions = append(suggestions, Suggestion{
-					Text: creationStr,
-					Type: "creation date",
-				})
-				seen[key] = true
-			}
-		}
-
-		if strings.Contains(strings.ToLower(artist.FirstAlbum), query) {
-			key := artist.FirstAlbum + "-album"
-			if !seen[key] {
-				suggestions = append(suggestions, Suggestion{
-					Text: artist.FirstAlbum,
-					Type: "first album date",
-				})
-				seen[key] = true
-			}
+		if result, ok := parsed.Evaluate(&artists[i], catalog); ok {
+			results = append(results, result)
 		}
 	}
+	rankResults(results)
 
-	if len(suggestions) > 10 {
-		suggestions = suggestions[:10]
-	}
-
-	return suggestions, nil
+	return results, nil
 }
@@ -0,0 +1,11 @@
+package services
+
+import "groupie-tracker/internal/api"
+
+type Service struct {
+	client *api.Client
+}
+
+func New(client *api.Client) *Service {
+	return &Service{client: client}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"groupie-tracker/internal/textindex"
+	"groupie-tracker/internal/textnorm"
+)
+
+const (
+	DefaultSuggestionLimit = 10
+	MaxSuggestionLimit     = 50
+)
+
+type Suggestion struct {
+	Text    string `json:"text"`
+	Type    string `json:"type"`
+	ID      int    `json:"id,omitempty"`
+	URL     string `json:"url"`
+	Artists int    `json:"artists"`
+}
+
+type SuggestionOptions struct {
+	Limit int
+	Types []string
+}
+
+var suggestionTypes = map[string]string{
+	FieldName:     "artist/band",
+	FieldMember:   "member",
+	FieldCreated:  "creation date",
+	FieldAlbum:    "first album date",
+	FieldLocation: "location",
+	FieldDate:     "concert date",
+}
+
+var suggestionOrder = []string{FieldName, FieldMember, FieldLocation, FieldCreated, FieldAlbum, FieldDate}
+
+var suggestionTypeNames = map[string]string{
+	"artist":   FieldName,
+	"name":     FieldName,
+	"member":   FieldMember,
+	"location": FieldLocation,
+	"created":  FieldCreated,
+	"album":    FieldAlbum,
+	"date":     FieldDate,
+}
+
+const (
+	suggestInfix = iota
+	suggestWordPrefix
+	suggestPrefix
+	suggestExact
+)
+
+func ParseSuggestionOptions(limit, types string) (SuggestionOptions, error) {
+	opts := SuggestionOptions{Limit: DefaultSuggestionLimit}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return SuggestionOptions{}, fmt.Errorf("invalid limit %q", limit)
+		}
+		opts.Limit = min(n, MaxSuggestionLimit)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(types, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		field, ok := suggestionTypeNames[name]
+		if !ok {
+			return SuggestionOptions{}, fmt.Errorf("unknown suggestion type %q", name)
+		}
+		if !seen[field] {
+			seen[field] = true
+			opts.Types = append(opts.Types, field)
+		}
+	}
+	return opts, nil
+}
+
+func (s *Service) GetSuggestions(query string) ([]Suggestion, error) {
+	return s.GetSuggestionsContext(context.Background(), query)
+}
+
+func (s *Service) GetSuggestionsContext(ctx context.Context, query string) ([]Suggestion, error) {
+	return s.SuggestContext(ctx, query, SuggestionOptions{})
+}
+
+func (s *Service) Suggest(query string, opts SuggestionOptions) ([]Suggestion, error) {
+	return s.SuggestContext(context.Background(), query, opts)
+}
+
+func (s *Service) SuggestContext(ctx context.Context, query string, opts SuggestionOptions) ([]Suggestion, error) {
+	query = textnorm.Fold(query)
+	if query == "" {
+		return []Suggestion{}, nil
+	}
+
+	catalog, err := s.client.CatalogContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+	types := opts.Types
+	if len(types) == 0 {
+		types = suggestionOrder
+	}
+
+	type candidate struct {
+		entry *textindex.Entry
+		rank  int
+		order int
+	}
+	index := catalog.Index()
+	var candidates []candidate
+	present := make(map[string]bool)
+	for _, i := range index.Search(query, types...) {
+		entry := index.Entry(i)
+		if !entry.Contains(query) {
+			continue
+		}
+		present[entry.Field] = true
+		candidates = append(candidates, candidate{entry: entry, rank: suggestionRank(entry.Key, query), order: i})
+	}
+
+	priority := make(map[string]int, len(suggestionOrder))
+	for i, field := range suggestionOrder {
+		priority[field] = i
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.rank != b.rank {
+			return a.rank > b.rank
+		}
+		if a.entry.Field != b.entry.Field {
+			return priority[a.entry.Field] < priority[b.entry.Field]
+		}
+		return a.order < b.order
+	})
+
+	if len(candidates) == 0 {
+		return []Suggestion{}, nil
+	}
+	quota := limit / len(present)
+	if quota == 0 || limit%len(present) != 0 {
+		quota++
+	}
+
+	picked := make([]bool, len(candidates))
+	counts := make(map[string]int)
+	taken := 0
+	for i, c := range candidates {
+		if taken < limit && counts[c.entry.Field] < quota {
+			picked[i] = true
+			counts[c.entry.Field]++
+			taken++
+		}
+	}
+	for i := range candidates {
+		if taken < limit && !picked[i] {
+			picked[i] = true
+			taken++
+		}
+	}
+
+	suggestions := []Suggestion{}
+	for i, c := range candidates {
+		if picked[i] {
+			suggestions = append(suggestions, newSuggestion(c.entry))
+		}
+	}
+	return suggestions, nil
+}
+
+func suggestionRank(key, query string) int {
+	switch {
+	case key == query:
+		return suggestExact
+	case strings.HasPrefix(key, query):
+		return suggestPrefix
+	case strings.Contains(key, " "+query):
+		return suggestWordPrefix
+	}
+	return suggestInfix
+}
+
+func newSuggestion(entry *textindex.Entry) Suggestion {
+	suggestion := Suggestion{
+		Text:    entry.Text,
+		Type:    suggestionTypes[entry.Field],
+		Artists: len(entry.IDs),
+	}
+	if len(entry.IDs) == 1 {
+		suggestion.ID = entry.IDs[0]
+		suggestion.URL = "/artist/" + strconv.Itoa(entry.IDs[0])
+	} else {
+		suggestion.URL = "/search?q=" + url.QueryEscape(searchTerm(entry))
+	}
+	return suggestion
+}
+
+func searchTerm(entry *textindex.Entry) string {
+	return entry.Field + `:"` + strings.ReplaceAll(entry.Text, `"`, "") + `"`
+}
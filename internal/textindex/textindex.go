@@ -0,0 +1,212 @@
+package textindex
+
+import (
+	"strings"
+	"unicode"
+
+	"groupie-tracker/internal/textnorm"
+)
+
+type Token struct {
+	Text       string
+	Start, End int
+}
+
+func Tokenize(s string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range s {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, Token{Text: s[start:i], Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Text: s[start:], Start: start, End: len(s)})
+	}
+	return tokens
+}
+
+type Entry struct {
+	Field string
+	Text  string
+	Key   string
+	IDs   []int
+
+	terms string
+}
+
+func (e *Entry) Contains(query string) bool {
+	return strings.Contains(e.terms, query)
+}
+
+type entryKey struct {
+	field, text string
+}
+
+type node struct {
+	children map[rune]*node
+	entries  []int
+}
+
+type Index struct {
+	entries    []Entry
+	keys       map[entryKey]int
+	root       *node
+	words      map[string]map[string][]int
+	vocabulary map[string][]string
+}
+
+func New() *Index {
+	return &Index{
+		keys:       make(map[entryKey]int),
+		root:       &node{},
+		words:      make(map[string]map[string][]int),
+		vocabulary: make(map[string][]string),
+	}
+}
+
+func (ix *Index) Add(field, text string, id int, aliases ...string) {
+	if text == "" {
+		return
+	}
+
+	key := entryKey{field, text}
+	if i, ok := ix.keys[key]; ok {
+		entry := &ix.entries[i]
+		if entry.IDs[len(entry.IDs)-1] != id {
+			entry.IDs = append(entry.IDs, id)
+		}
+		return
+	}
+
+	i := len(ix.entries)
+	terms := textnorm.Fold(strings.Join(append([]string{text}, aliases...), " "))
+	ix.entries = append(ix.entries, Entry{Field: field, Text: text, Key: textnorm.Fold(text), IDs: []int{id}, terms: terms})
+	ix.keys[key] = i
+
+	words := ix.words[field]
+	if words == nil {
+		words = make(map[string][]int)
+		ix.words[field] = words
+	}
+	for _, token := range Tokenize(terms) {
+		postings := words[token.Text]
+		if len(postings) > 0 && postings[len(postings)-1] == i {
+			continue
+		}
+		if postings == nil {
+			ix.vocabulary[field] = append(ix.vocabulary[field], token.Text)
+		}
+		words[token.Text] = append(postings, i)
+
+		runes := []rune(token.Text)
+		for start := range runes {
+			ix.insert(runes[start:], i)
+		}
+	}
+}
+
+func (ix *Index) insert(suffix []rune, entry int) {
+	n := ix.root
+	for _, r := range suffix {
+		child := n.children[r]
+		if child == nil {
+			child = &node{}
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			n.children[r] = child
+		}
+		n = child
+		if len(n.entries) == 0 || n.entries[len(n.entries)-1] != entry {
+			n.entries = append(n.entries, entry)
+		}
+	}
+}
+
+func (ix *Index) Entry(i int) *Entry {
+	return &ix.entries[i]
+}
+
+func (ix *Index) Len() int {
+	return len(ix.entries)
+}
+
+func (ix *Index) Search(query string, fields ...string) []int {
+	var result []int
+	tokens := Tokenize(textnorm.Fold(query))
+	if len(tokens) == 0 {
+		result = make([]int, len(ix.entries))
+		for i := range result {
+			result[i] = i
+		}
+	}
+	for i, token := range tokens {
+		postings := ix.lookup(token.Text)
+		if i == 0 {
+			result = postings
+		} else {
+			result = intersect(result, postings)
+		}
+		if len(result) == 0 {
+			return nil
+		}
+	}
+	return ix.filter(result, fields)
+}
+
+func (ix *Index) lookup(word string) []int {
+	n := ix.root
+	for _, r := range word {
+		if n = n.children[r]; n == nil {
+			return nil
+		}
+	}
+	return n.entries
+}
+
+func (ix *Index) filter(entries []int, fields []string) []int {
+	if len(fields) == 0 {
+		return entries
+	}
+	var result []int
+	for _, i := range entries {
+		for _, field := range fields {
+			if ix.entries[i].Field == field {
+				result = append(result, i)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (ix *Index) Words(field string) []string {
+	return ix.vocabulary[field]
+}
+
+func (ix *Index) WordEntries(field, word string) []int {
+	return ix.words[field][word]
+}
+
+func intersect(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
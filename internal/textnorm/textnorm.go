@@ -0,0 +1,86 @@
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Offset struct {
+	Start, End int
+}
+
+var foldTable = map[rune]string{}
+
+func init() {
+	for base, letters := range map[string]string{
+		"a": "àáâãäåāăąǎạả", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęěẹẻẽ",
+		"g": "ĝğġģ", "h": "ĥħ", "i": "ìíîïĩīĭįıǐịỉ", "j": "ĵ", "k": "ķ",
+		"l": "ĺļľŀł", "n": "ñńņňŉ", "o": "òóôõöøōŏőǒơọỏ", "r": "ŕŗř",
+		"s": "śŝşšș", "t": "ţťŧț", "u": "ùúûüũūŭůűųǔưụủ", "w": "ŵ",
+		"y": "ýÿŷỳỹ", "z": "źżž", "ss": "ß", "ae": "æ", "oe": "œ", "th": "þ",
+	} {
+		for _, r := range letters {
+			foldTable[r] = base
+		}
+	}
+}
+
+func Fold(s string) string {
+	folded, _ := fold(s, false)
+	return folded
+}
+
+func FoldOffsets(s string) (string, []Offset) {
+	return fold(s, true)
+}
+
+func fold(s string, track bool) (string, []Offset) {
+	var b strings.Builder
+	var offsets []Offset
+	pending, gap := false, Offset{}
+	last := -1
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		start, end := i, i+size
+		i = end
+
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			if track && last >= 0 {
+				for j := last; j < len(offsets); j++ {
+					offsets[j].End = end
+				}
+			}
+			continue
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			if !pending {
+				pending, gap = true, Offset{start, end}
+			}
+			continue
+		}
+
+		if pending && b.Len() > 0 {
+			b.WriteByte(' ')
+			if track {
+				offsets = append(offsets, gap)
+			}
+		}
+		pending = false
+
+		r = unicode.ToLower(r)
+		text, ok := foldTable[r]
+		if !ok {
+			text = string(r)
+		}
+		last = b.Len()
+		b.WriteString(text)
+		if track {
+			for j := 0; j < len(text); j++ {
+				offsets = append(offsets, Offset{start, end})
+			}
+		}
+	}
+	return b.String(), offsets
+}
@@ -13,8 +13,14 @@ func ErrorHandler(w http.ResponseWriter, status int) {
 		message = "404 - Page Not Found"
 	case http.StatusBadRequest:
 		message = "400 - Bad Request"
+	case http.StatusMethodNotAllowed:
+		message = "405 - Method Not Allowed"
 	case http.StatusInternalServerError:
 		message = "500 - Internal Server Error"
+	case http.StatusServiceUnavailable:
+		message = "503 - Service Unavailable"
+	case http.StatusGatewayTimeout:
+		message = "504 - Gateway Timeout"
 	default:
 		message = "Error"
 	}
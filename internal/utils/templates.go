@@ -5,11 +5,11 @@ import (
 	"html/template"
 	"net/http"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"time"
 )
 
+var TemplateDir = filepath.Join("web", "templates")
+
 var templates *template.Template
 
 type PageData struct {
@@ -18,32 +18,14 @@ type PageData struct {
 	SearchQuery     string
 	SearchExpanded  bool
 	ContentTemplate string
+	Outdated        bool
 	Data            interface{}
 }
 
-func formatLocation(loc string) string {
-	loc = strings.ReplaceAll(loc, "_", " ")
-	parts := strings.Split(loc, "-")
-	for i, part := range parts {
-		parts[i] = strings.Title(strings.TrimSpace(part))
-	}
-	return strings.Join(parts, ", ")
-}
-
-func formatDate(dateStr string) string {
-	parts := strings.Split(dateStr, "-")
-	if len(parts) != 3 {
-		return dateStr
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "Unknown"
 	}
-	day, _ := strconv.Atoi(parts[0])
-	month, _ := strconv.Atoi(parts[1])
-	year, _ := strconv.Atoi(parts[2])
-
-	if month < 1 || month > 12 {
-		return dateStr
-	}
-
-	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 	return t.Format("2 January 2006")
 }
 
@@ -57,9 +39,8 @@ func InitTemplates() error {
 			}
 			return string(bytes), nil
 		},
-		"formatLocation": formatLocation,
-		"formatDate":     formatDate,
-	}).ParseGlob(filepath.Join("web", "templates", "*.html"))
+		"formatDate": formatDate,
+	}).ParseGlob(filepath.Join(TemplateDir, "*.html"))
 	return err
 }
 
@@ -3,12 +3,16 @@ package main
 import (
 	"flag"
 	"fmt"
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/cassette"
 	"groupie-tracker/internal/handlers"
+	"groupie-tracker/internal/services"
 	"groupie-tracker/internal/utils"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"time"
 )
@@ -31,30 +35,112 @@ func openBrowser(url string) {
 	}
 }
 
+func envOr(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
 func main() {
 	defaultPort := "8080"
 	if envPort := os.Getenv("PORT"); envPort != "" {
 		defaultPort = envPort
 	}
 	addr := flag.String("addr", ":"+defaultPort, "HTTP network address")
+	sourceKind := flag.String("source", envOr("GROUPIE_SOURCE", "http"), "data source: http, file or memory")
+	sourceLocation := flag.String("source-location", os.Getenv("GROUPIE_SOURCE_LOCATION"), "upstream base URL or data directory for the data source; a comma-separated list of [name=]location federates several upstreams")
+	dataDir := flag.String("data-dir", envOr("GROUPIE_DATA_DIR", "data"), "directory where upstream snapshots are stored")
+	importsDir := flag.String("imports", os.Getenv("GROUPIE_IMPORTS"), "directory of CSV/JSON files with local artists to merge into the catalog")
+	overridesPath := flag.String("overrides", os.Getenv("GROUPIE_OVERRIDES"), "JSON file with local patches applied on top of upstream data")
+	offline := flag.Bool("offline", false, "serve only from the newest snapshot in -data-dir")
+	upstreamTimeout := flag.Duration("upstream-timeout", 30*time.Second, "timeout for a full upstream refresh")
+	geocodeTimeout := flag.Duration("geocode-timeout", 5*time.Second, "timeout for a single geocoding request")
+	requestTimeout := flag.Duration("request-timeout", 15*time.Second, "deadline for handling a single request")
+	driftThreshold := flag.Float64("drift-threshold", api.DriftThreshold, "fraction of upstream records that may miss a known field before a section is rejected")
+	fuzzyThreshold := flag.Float64("fuzzy-threshold", services.FuzzyThreshold, "fraction of a search word's letters that may differ in a typo-tolerant match (0 disables)")
+	cassettePath := flag.String("cassette", os.Getenv("GROUPIE_CASSETTE"), "cassette file to record or replay upstream and geocoder HTTP traffic")
+	cassetteMode := flag.String("cassette-mode", envOr("GROUPIE_CASSETTE_MODE", "replay"), "cassette mode: record, replay or strict")
 	flag.Parse()
 
+	store := api.NewSnapshotStore(*dataDir)
+	services.GeocodeTimeout = *geocodeTimeout
+	api.DriftThreshold = *driftThreshold
+	services.FuzzyThreshold = *fuzzyThreshold
+
+	var overrides *api.Overrides
+	if *overridesPath != "" {
+		var err error
+		overrides, err = api.LoadOverrides(*overridesPath)
+		if err != nil {
+			log.Fatal("Failed to load overrides:", err)
+		}
+	}
+
+	var imports *api.Imports
+	if *importsDir != "" {
+		var err error
+		imports, err = api.LoadImports(*importsDir)
+		if err != nil {
+			log.Fatal("Failed to load imports:", err)
+		}
+		log.Printf("Imported %d local artist(s) from %s", len(imports.Artists), *importsDir)
+	}
+
+	var source api.DataSource
+	if *offline {
+		source = api.NewSnapshotSource(store)
+	} else {
+		var err error
+		source, err = api.OpenSource(*sourceKind, *sourceLocation)
+		if err != nil {
+			log.Fatal("Failed to open data source:", err)
+		}
+	}
+
+	if *cassettePath != "" {
+		mode, err := cassette.ParseMode(*cassetteMode)
+		if err != nil {
+			log.Fatal(err)
+		}
+		recorder, err := cassette.New(*cassettePath, mode)
+		if err != nil {
+			log.Fatal("Failed to open cassette:", err)
+		}
+		if s, ok := source.(interface{ UseTransport(http.RoundTripper) }); ok {
+			s.UseTransport(recorder)
+		}
+		services.UseGeocodeTransport(recorder)
+		log.Printf("Using cassette %s (%s mode)", *cassettePath, *cassetteMode)
+	}
+
+	client := api.NewClient(source)
+	client.FetchTimeout = *upstreamTimeout
+	client.UseImports(imports)
+	client.UseOverrides(overrides)
+
+	if *offline {
+		if _, err := client.FetchAPI(); err != nil {
+			log.Fatal("Failed to load snapshot:", err)
+		}
+		log.Printf("Offline mode: serving snapshot from %s", *dataDir)
+	} else {
+		if err := client.UseSnapshots(store); err != nil {
+			log.Println("No snapshot loaded:", err)
+		}
+		client.Start()
+	}
+
+	h := handlers.New(client)
+	h.RequestTimeout = *requestTimeout
+
 	if err := utils.InitTemplates(); err != nil {
 		log.Fatal("Failed to load templates:", err)
 	}
 
-	mux := http.NewServeMux()
-	fs := http.FileServer(http.Dir("web/static"))
-	mux.Handle("/static/", http.StripPrefix("/static/", fs))
-	mux.HandleFunc("/", handlers.HomeHandler)
-	mux.HandleFunc("/artist/", handlers.ArtistHandler)
-	mux.HandleFunc("/search", handlers.SearchHandler)
-	mux.HandleFunc("/api/suggestions", handlers.SuggestionsHandler)
-	mux.HandleFunc("/map/", handlers.GeoHandler)
-
 	server := &http.Server{
 		Addr:    *addr,
-		Handler: mux,
+		Handler: handlers.NewMux(h, filepath.Join("web", "static")),
 	}
 
 	url := fmt.Sprintf("http://localhost%s", *addr)
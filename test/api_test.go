@@ -1,13 +1,14 @@
 package test
 
 import (
+	"context"
 	"testing"
 
 	"groupie-tracker/internal/api"
 )
 
 func TestFetchAPI(t *testing.T) {
-	data, err := api.FetchAPI()
+	data, err := newTestClient().FetchAPI()
 	if err != nil {
 		t.Fatalf("FetchAPI failed: %v", err)
 	}
@@ -24,7 +25,7 @@ func TestFetchAPI(t *testing.T) {
 }
 
 func TestGetArtistByID(t *testing.T) {
-	artist, err := api.GetArtistByID(1)
+	artist, err := newTestClient().GetArtistByID(1)
 	if err != nil {
 		t.Fatalf("GetArtistByID failed: %v", err)
 	}
@@ -41,7 +42,7 @@ func TestGetArtistByID(t *testing.T) {
 }
 
 func TestGetRelationByID(t *testing.T) {
-	relation, err := api.GetRelationByID(1)
+	relation, err := newTestClient().GetRelationByID(1)
 	if err != nil {
 		t.Fatalf("GetRelationByID failed: %v", err)
 	}
@@ -56,3 +57,44 @@ func TestGetRelationByID(t *testing.T) {
 
 	t.Logf("Fetched relation with %d locations", len(relation.DatesLocations))
 }
+
+func TestMemorySource(t *testing.T) {
+	data, err := api.NewFileSource(fixtureDir).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("FileSource.Fetch failed: %v", err)
+	}
+
+	client := api.NewClient(api.NewMemorySource(data))
+	artist, err := client.GetArtistByID(2)
+	if err != nil {
+		t.Fatalf("GetArtistByID failed: %v", err)
+	}
+
+	if artist.Name != "SOJA" {
+		t.Errorf("Expected SOJA, got %s", artist.Name)
+	}
+}
+
+func TestOpenSource(t *testing.T) {
+	if _, err := api.OpenSource("ftp", ""); err == nil {
+		t.Error("Expected error for unknown source kind")
+	}
+
+	if _, err := api.OpenSource("file", ""); err == nil {
+		t.Error("Expected error for file source without directory")
+	}
+
+	source, err := api.OpenSource("memory", fixtureDir)
+	if err != nil {
+		t.Fatalf("OpenSource failed: %v", err)
+	}
+
+	data, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if len(data.Artists) == 0 {
+		t.Error("Expected artists, got none")
+	}
+}
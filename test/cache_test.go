@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"groupie-tracker/internal/api"
+)
+
+type flakySource struct {
+	mu    sync.Mutex
+	data  *api.APIData
+	fail  bool
+	calls int
+}
+
+func (s *flakySource) Fetch(ctx context.Context) (*api.APIData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.fail {
+		return nil, errors.New("upstream down")
+	}
+	return s.data, nil
+}
+
+func (s *flakySource) setFail(fail bool) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+func newFlakySource(t *testing.T) *flakySource {
+	return &flakySource{data: loadFixture(t)}
+}
+
+func TestCacheServesStaleOnFailure(t *testing.T) {
+	source := newFlakySource(t)
+	client := api.NewClient(source)
+
+	if _, err := client.FetchAPI(); err != nil {
+		t.Fatalf("FetchAPI failed: %v", err)
+	}
+
+	source.setFail(true)
+	if err := client.Refresh(); err == nil {
+		t.Fatal("Expected refresh to fail")
+	}
+
+	data, err := client.FetchAPI()
+	if err != nil {
+		t.Fatalf("Expected stale data, got error: %v", err)
+	}
+	if len(data.Artists) == 0 {
+		t.Error("Expected artists from stale snapshot")
+	}
+
+	status := client.Status()
+	if !status.Loaded || status.LastError == "" || status.Failures != 1 {
+		t.Errorf("Unexpected status after failed refresh: %+v", status)
+	}
+
+	source.setFail(false)
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if status := client.Status(); status.LastError != "" || status.Failures != 0 {
+		t.Errorf("Expected status to recover, got %+v", status)
+	}
+}
+
+func TestCacheConcurrentFirstLoad(t *testing.T) {
+	source := newFlakySource(t)
+	client := api.NewClient(source)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.FetchAPI(); err != nil {
+				t.Errorf("FetchAPI failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if source.calls != 1 {
+		t.Errorf("Expected a single upstream fetch, got %d", source.calls)
+	}
+}
+
+func TestBackgroundRefresher(t *testing.T) {
+	source := newFlakySource(t)
+	client := api.NewClient(source)
+	client.Start()
+	defer client.Stop()
+
+	if _, err := client.FetchAPI(); err != nil {
+		t.Fatalf("FetchAPI failed: %v", err)
+	}
+	if !client.Status().Loaded {
+		t.Error("Expected cache to be loaded")
+	}
+}
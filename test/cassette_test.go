@@ -0,0 +1,105 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/cassette"
+	"groupie-tracker/internal/httpclient"
+)
+
+func cassetteClient(url string, recorder *cassette.Recorder) *api.Client {
+	source := api.NewHTTPSource(url)
+	config := testHTTPConfig()
+	config.MaxBodySize = 0
+	source.Client = httpclient.New(config)
+	source.UseTransport(recorder)
+	return api.NewClient(source)
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upstream.json")
+	server := newFixtureServer(t)
+	url := server.URL
+
+	recorder, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	client := cassetteClient(url, recorder)
+	recorded, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog while recording failed: %v", err)
+	}
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh while recording failed: %v", err)
+	}
+	if server.notModifiedCount() == 0 {
+		t.Fatal("Expected conditional responses while refreshing")
+	}
+	interactions := recorder.Interactions()
+	if len(interactions) != 4 {
+		t.Fatalf("Expected 4 recorded interactions, got %d", len(interactions))
+	}
+	for _, interaction := range interactions {
+		if interaction.Response.StatusCode != http.StatusOK {
+			t.Errorf("Expected %s to keep its full response, got %d", interaction.Request.URL, interaction.Response.StatusCode)
+		}
+	}
+
+	server.Close()
+
+	replayer, err := cassette.New(path, cassette.ModeStrict)
+	if err != nil {
+		t.Fatalf("Failed to open recorded cassette: %v", err)
+	}
+	replayed, err := cassetteClient(url, replayer).Catalog()
+	if err != nil {
+		t.Fatalf("Catalog while replaying failed: %v", err)
+	}
+	if len(replayed.Artists()) != len(recorded.Artists()) || len(replayed.Concerts()) != len(recorded.Concerts()) {
+		t.Error("Expected replayed catalog to match the recorded one")
+	}
+}
+
+func TestCassetteReplayPassesThrough(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upstream.json")
+	server := newFixtureServer(t)
+
+	replayer, err := cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	if _, err := cassetteClient(server.URL, replayer).Catalog(); err != nil {
+		t.Fatalf("Catalog with an empty replay cassette failed: %v", err)
+	}
+	if got := len(replayer.Interactions()); got != 0 {
+		t.Errorf("Expected replay mode not to record, got %d interactions", got)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected replay mode not to write the cassette, got %v", err)
+	}
+}
+
+func TestCassetteStrictMode(t *testing.T) {
+	if _, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeStrict); err == nil {
+		t.Error("Expected strict mode to require an existing cassette")
+	}
+
+	recorder, err := cassette.New(filepath.Join("testdata", "cassettes", "nominatim.json"), cassette.ModeStrict)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://nominatim.openstreetmap.org/search?format=json&limit=1&q=Atlantis", nil)
+	if _, err := recorder.RoundTrip(req); !errors.Is(err, cassette.ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+
+	if _, err := cassette.ParseMode("rewind"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+)
+
+func TestCatalogIndexes(t *testing.T) {
+	catalog, err := newTestClient().Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	if artist, ok := catalog.Artist(3); !ok || artist.Name != "Pink Floyd" {
+		t.Errorf("Expected Pink Floyd for ID 3, got %v", artist)
+	}
+
+	if _, ok := catalog.Artist(999); ok {
+		t.Error("Expected no artist for ID 999")
+	}
+
+	if ids := catalog.ArtistsWithMember("freddie mercury"); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("Expected Queen for Freddie Mercury, got %v", ids)
+	}
+
+	if ids := catalog.ArtistsAtLocation("sao_paulo-brazil"); len(ids) != 3 {
+		t.Errorf("Expected 3 artists in Sao Paulo, got %v", ids)
+	}
+
+	created := catalog.CreatedBetween(1965, 1965)
+	if len(created) != 2 || !created[3] || !created[4] {
+		t.Errorf("Expected Pink Floyd and Scorpions created in 1965, got %v", created)
+	}
+}
+
+func TestLocationFilter(t *testing.T) {
+	params := services.FilterParams{
+		CreationDateMin: 0,
+		CreationDateMax: 9999,
+		FirstAlbumMin:   0,
+		FirstAlbumMax:   9999,
+		MembersMin:      0,
+		MembersMax:      100,
+		Locations:       []string{"germany"},
+	}
+
+	results, err := newTestService().ApplyFilters(params)
+	if err != nil {
+		t.Fatalf("ApplyFilters failed: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "Scorpions" {
+		t.Errorf("Expected only Scorpions to have played in Germany, got %v", results)
+	}
+}
+
+func syntheticData(n int) *api.APIData {
+	data := &api.APIData{}
+	for i := 1; i <= n; i++ {
+		data.Artists = append(data.Artists, api.Artist{
+			ID:           i,
+			Name:         fmt.Sprintf("Artist %d", i),
+			Members:      []string{fmt.Sprintf("Member %d", i), fmt.Sprintf("Member %d", i+1)},
+			CreationDate: 1950 + i%70,
+			FirstAlbum:   fmt.Sprintf("01-01-%d", 1955+i%70),
+		})
+		data.Relations.Index = append(data.Relations.Index, api.Relation{
+			ID: i,
+			DatesLocations: map[string][]string{
+				fmt.Sprintf("city_%d-country_%d", i%500, i%50): {"01-01-2020"},
+			},
+		})
+	}
+	return data
+}
+
+func BenchmarkCatalogLookup(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		catalog := api.NewCatalog(syntheticData(n))
+		b.Run(fmt.Sprintf("artist/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				catalog.Artist(i%n + 1)
+			}
+		})
+		b.Run(fmt.Sprintf("relation/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				catalog.Relation(i%n + 1)
+			}
+		})
+		b.Run(fmt.Sprintf("member/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				catalog.ArtistsWithMember("member 42")
+			}
+		})
+		b.Run(fmt.Sprintf("location/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				catalog.ArtistsAtLocation("city_42-country_42")
+			}
+		})
+		b.Run(fmt.Sprintf("played-at/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				catalog.PlayedAt([]string{"city_42-country_42"})
+			}
+		})
+	}
+}
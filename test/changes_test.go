@@ -0,0 +1,102 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+)
+
+func countKind(changes []api.Change, kind string) int {
+	n := 0
+	for _, change := range changes {
+		if change.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDiffCatalogs(t *testing.T) {
+	before := loadFixture(t)
+	after := loadFixture(t)
+
+	after.Artists = append(after.Artists[:5:5], after.Artists[6:]...)
+	after.Artists[0].Members = append([]string{"Adam Lambert"}, after.Artists[0].Members[1:]...)
+	after.Artists = append(after.Artists, api.Artist{ID: 9, Name: "Newcomers"})
+	after.Relations.Index[2] = api.Relation{
+		ID: 3,
+		DatesLocations: map[string][]string{
+			"london-uk":          {"14-12-2019"},
+			"mexico_city-mexico": {"07-05-2019"},
+			"sao_paulo-brazil":   {"30-04-2019"},
+			"paris-france":       {"01-06-2020"},
+		},
+	}
+
+	changes := api.Diff(api.NewCatalog(before), api.NewCatalog(after))
+
+	expected := map[string]int{
+		api.ChangeArtistAdded:    1,
+		api.ChangeArtistRemoved:  1,
+		api.ChangeMemberAdded:    1,
+		api.ChangeMemberRemoved:  1,
+		api.ChangeConcertAdded:   1,
+		api.ChangeConcertRemoved: 1,
+	}
+	for kind, n := range expected {
+		if got := countKind(changes, kind); got != n {
+			t.Errorf("Expected %d %q, got %d", n, kind, got)
+		}
+	}
+}
+
+func TestChangeLogCursor(t *testing.T) {
+	dir := t.TempDir()
+	changeLog, err := api.OpenChangeLog(dir)
+	if err != nil {
+		t.Fatalf("OpenChangeLog failed: %v", err)
+	}
+
+	now := time.Now()
+	changeLog.Append(now, []api.Change{{Kind: api.ChangeArtistAdded}, {Kind: api.ChangeMemberAdded}})
+	changeLog.Append(now.Add(time.Minute), []api.Change{{Kind: api.ChangeConcertAdded}})
+
+	changes, cursor := changeLog.Since(2)
+	if cursor != 3 || len(changes) != 1 || changes[0].Kind != api.ChangeConcertAdded {
+		t.Errorf("Unexpected changes since 2: %+v (cursor %d)", changes, cursor)
+	}
+
+	reopened, err := api.OpenChangeLog(dir)
+	if err != nil {
+		t.Fatalf("OpenChangeLog failed: %v", err)
+	}
+	if changes, cursor := reopened.Since(0); len(changes) != 3 || cursor != 3 {
+		t.Errorf("Expected persisted changes, got %d (cursor %d)", len(changes), cursor)
+	}
+}
+
+func TestClientRecordsChanges(t *testing.T) {
+	server := newFixtureServer(t)
+	client := server.client()
+
+	if _, err := client.FetchAPI(); err != nil {
+		t.Fatalf("FetchAPI failed: %v", err)
+	}
+	if changes, _ := client.Changes(0); len(changes) != 0 {
+		t.Errorf("Expected no changes after first fetch, got %d", len(changes))
+	}
+
+	server.setBody("relation", []byte(`{"index":[{"id":1,"datesLocations":{"osaka-japan":["28-01-2020"]}}]}`))
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	changes, cursor := client.Changes(0)
+	if countKind(changes, api.ChangeConcertRemoved) == 0 {
+		t.Errorf("Expected removed concerts, got %+v", changes)
+	}
+	if more, _ := client.Changes(cursor); len(more) != 0 {
+		t.Errorf("Expected no changes after cursor, got %d", len(more))
+	}
+}
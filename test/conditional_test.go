@@ -0,0 +1,46 @@
+package test
+
+import (
+	"testing"
+)
+
+func TestConditionalRefresh(t *testing.T) {
+	server := newFixtureServer(t)
+	client := server.client()
+
+	first, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if server.notModifiedCount() != 0 {
+		t.Fatal("Expected full responses on first fetch")
+	}
+
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := server.notModifiedCount(); got != 4 {
+		t.Errorf("Expected 4 not-modified responses, got %d", got)
+	}
+
+	second, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if len(second.Artists()) != len(first.Artists()) || len(second.Concerts()) != len(first.Concerts()) {
+		t.Error("Expected unchanged sections to be kept on 304")
+	}
+
+	server.setBody("artists", []byte(`[{"id":1,"name":"Queen Revisited","members":["Brian May"],"creationDate":1970,"firstAlbum":"14-12-1973","image":"","locations":"","concertDates":"","relations":""}]`))
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := server.notModifiedCount(); got != 7 {
+		t.Errorf("Expected only artists to be downloaded again, got %d not-modified responses", got)
+	}
+
+	artist, err := client.GetArtistByID(1)
+	if err != nil || artist.Name != "Queen Revisited" {
+		t.Errorf("Expected changed artists section, got %v, %v", artist, err)
+	}
+}
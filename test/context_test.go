@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+)
+
+type blockingSource struct {
+	started chan struct{}
+}
+
+func (s *blockingSource) Fetch(ctx context.Context) (*api.APIData, error) {
+	close(s.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCatalogContextCancel(t *testing.T) {
+	source := &blockingSource{started: make(chan struct{})}
+	client := api.NewClient(source)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := client.CatalogContext(ctx)
+		errChan <- err
+	}()
+
+	<-source.started
+	cancel()
+
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CatalogContext did not return after cancellation")
+	}
+
+	if status := client.Status(); status.Failures != 0 {
+		t.Errorf("Expected cancelled fetch not to count as failure, got %+v", status)
+	}
+}
+
+func TestFetchTimeout(t *testing.T) {
+	client := api.NewClient(&blockingSource{started: make(chan struct{})})
+	client.FetchTimeout = 20 * time.Millisecond
+
+	_, err := client.CatalogContext(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if status := client.Status(); status.Failures != 1 {
+		t.Errorf("Expected timeout to count as failure, got %+v", status)
+	}
+}
+
+func TestHTTPSourceStopsOnCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := api.NewHTTPSource(server.URL).Fetch(ctx)
+	if err == nil {
+		t.Fatal("Expected error from cancelled fetch")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fetch took %v after cancellation", elapsed)
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+)
+
+func TestParsePlace(t *testing.T) {
+	tests := []struct {
+		slug     string
+		city     string
+		region   string
+		country  string
+		expected string
+	}{
+		{"north_carolina-usa", "North Carolina", "", "USA", "North Carolina, USA"},
+		{"sao_paulo-brazil", "Sao Paulo", "", "Brazil", "Sao Paulo, Brazil"},
+		{"london-uk", "London", "", "UK", "London, UK"},
+		{"springfield-illinois-usa", "Springfield", "Illinois", "USA", "Springfield, Illinois, USA"},
+		{"évry-france", "Évry", "", "France", "Évry, France"},
+		{"östersund-sweden", "Östersund", "", "Sweden", "Östersund, Sweden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			place := api.ParsePlace(tt.slug)
+			if place.City != tt.city || place.Region != tt.region || place.Country != tt.country {
+				t.Errorf("ParsePlace(%q) = %+v", tt.slug, place)
+			}
+			if place.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, place.String())
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	date, err := api.ParseDate("14-12-1973")
+	if err != nil {
+		t.Fatalf("ParseDate failed: %v", err)
+	}
+	if !date.Equal(time.Date(1973, time.December, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date %v", date)
+	}
+
+	if _, err := api.ParseDate("31-13-2020"); err == nil {
+		t.Error("Expected error for invalid month")
+	}
+}
+
+func TestCatalogConcerts(t *testing.T) {
+	catalog, err := newTestClient().Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	concerts := catalog.ConcertsByArtist(3)
+	if len(concerts) != 4 {
+		t.Fatalf("Expected 4 Pink Floyd concerts, got %d", len(concerts))
+	}
+	for i := 1; i < len(concerts); i++ {
+		if concerts[i].Date.Before(concerts[i-1].Date) {
+			t.Errorf("Concerts not sorted by date: %v before %v", concerts[i-1].Date, concerts[i].Date)
+		}
+	}
+	if concerts[0].Artist.Name != "Pink Floyd" {
+		t.Errorf("Expected concert artist Pink Floyd, got %s", concerts[0].Artist.Name)
+	}
+
+	tour := catalog.Tour(3)
+	if len(tour) != 3 {
+		t.Fatalf("Expected 3 locations, got %d", len(tour))
+	}
+	for _, stop := range tour {
+		if stop.Location.City == "London" && len(stop.Dates) != 2 {
+			t.Errorf("Expected 2 London dates, got %d", len(stop.Dates))
+		}
+	}
+
+	artist, _ := catalog.Artist(1)
+	if artist.FirstAlbumDate.Year() != 1973 {
+		t.Errorf("Expected first album in 1973, got %v", artist.FirstAlbumDate)
+	}
+}
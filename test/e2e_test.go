@@ -0,0 +1,200 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/handlers"
+	"groupie-tracker/internal/httpclient"
+	"groupie-tracker/internal/services"
+	"groupie-tracker/internal/utils"
+	"groupie-tracker/test/fakeupstream"
+)
+
+type endToEnd struct {
+	upstream *fakeupstream.Server
+	client   *api.Client
+	handler  *handlers.Handler
+	site     *httptest.Server
+}
+
+func newEndToEnd(t *testing.T) *endToEnd {
+	utils.TemplateDir = filepath.Join("..", "web", "templates")
+	if err := utils.InitTemplates(); err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+
+	upstream := fakeupstream.Start(t, fixtureDir)
+	source := api.NewHTTPSource(upstream.BaseURL())
+	config := testHTTPConfig()
+	config.MaxBodySize = 0
+	config.BreakerThreshold = 0
+	source.Client = httpclient.New(config)
+
+	client := api.NewClient(source)
+	h := handlers.New(client)
+	site := httptest.NewServer(handlers.NewMux(h, filepath.Join("..", "web", "static")))
+	t.Cleanup(site.Close)
+
+	return &endToEnd{upstream: upstream, client: client, handler: h, site: site}
+}
+
+func (e *endToEnd) get(t *testing.T, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(e.site.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Reading %s failed: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestEndToEndPages(t *testing.T) {
+	e := newEndToEnd(t)
+
+	tests := []struct {
+		path     string
+		status   int
+		contains []string
+		excludes []string
+	}{
+		{"/", http.StatusOK, []string{"Queen", "Pink Floyd", "Gorillaz"}, []string{"Some data may be outdated"}},
+		{"/?creation_min=1990&creation_max=2000", http.StatusOK, []string{"SOJA"}, []string{"Queen"}},
+		{"/artist/1", http.StatusOK, []string{"Queen", "Freddie Mercury", "Osaka, Japan", "14 December 1973"}, nil},
+		{"/artist/99", http.StatusNotFound, nil, nil},
+		{"/artist/abc", http.StatusBadRequest, nil, nil},
+		{"/search?q=freddie", http.StatusOK, []string{"Queen", "Matched member: <mark>Freddie</mark> Mercury"}, []string{"Gorillaz"}},
+		{"/search?q=que", http.StatusOK, []string{"<mark>Que</mark>en"}, nil},
+		{"/search?q=osaka", http.StatusOK, []string{"Queen", "Matched location: <mark>Osaka</mark>, Japan"}, []string{"Gorillaz"}},
+		{"/search?q=qeeun", http.StatusOK, []string{`Did you mean <a href="/search?q=queen">queen</a>?`}, nil},
+		{"/search?q=genre:rock", http.StatusBadRequest, []string{"unknown field &#34;genre&#34;"}, nil},
+		{"/api/search?q=member:freddie", http.StatusOK, []string{`"name":"Queen"`}, []string{"Gorillaz"}},
+		{"/api/search?q=qeeun", http.StatusOK, []string{`"results":[]`, `"didYouMean":"queen"`}, nil},
+		{"/api/search?q=%28name:queen", http.StatusBadRequest, []string{`"error":"missing closing parenthesis"`, `"position":1`}, nil},
+		{"/static/css/style.css", http.StatusOK, []string{"outdated-banner"}, nil},
+		{"/missing", http.StatusNotFound, nil, nil},
+	}
+
+	for _, tt := range tests {
+		status, body := e.get(t, tt.path)
+		if status != tt.status {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.status, status)
+			continue
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(body, s) {
+				t.Errorf("GET %s: expected body to contain %q", tt.path, s)
+			}
+		}
+		for _, s := range tt.excludes {
+			if strings.Contains(body, s) {
+				t.Errorf("GET %s: expected body not to contain %q", tt.path, s)
+			}
+		}
+	}
+}
+
+func TestEndToEndSuggestions(t *testing.T) {
+	e := newEndToEnd(t)
+
+	status, body := e.get(t, "/api/suggestions?q=queen")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	var suggestions []services.Suggestion
+	if err := json.Unmarshal([]byte(body), &suggestions); err != nil {
+		t.Fatalf("Invalid suggestions JSON %q: %v", body, err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Text != "Queen" || suggestions[0].Type != "artist/band" {
+		t.Errorf("Unexpected suggestions: %+v", suggestions)
+	}
+
+	if suggestions[0].URL != "/artist/1" {
+		t.Errorf("Expected a link to the artist page, got %+v", suggestions[0])
+	}
+
+	if _, body := e.get(t, "/api/suggestions?q="); strings.TrimSpace(body) != "[]" {
+		t.Errorf("Expected empty suggestions for empty query, got %q", body)
+	}
+
+	status, body = e.get(t, "/api/suggestions?q=a&limit=2&types=artist,location")
+	suggestions = nil
+	if err := json.Unmarshal([]byte(body), &suggestions); err != nil || status != http.StatusOK || len(suggestions) != 2 {
+		t.Fatalf("Unexpected response %d %q", status, body)
+	}
+	for _, s := range suggestions {
+		if s.Type != "artist/band" && s.Type != "location" {
+			t.Errorf("Unexpected suggestion type %+v", s)
+		}
+	}
+
+	if status, body := e.get(t, "/api/suggestions?q=a&types=genre"); status != http.StatusBadRequest || !strings.Contains(body, `unknown suggestion type`) {
+		t.Errorf("Expected 400 for an unknown type, got %d %q", status, body)
+	}
+	if _, body := e.get(t, "/"); !strings.Contains(body, `id="suggestions"`) {
+		t.Error("Expected the layout to include the suggestions list")
+	}
+}
+
+func TestEndToEndUpstreamErrors(t *testing.T) {
+	e := newEndToEnd(t)
+	if status, _ := e.get(t, "/"); status != http.StatusOK {
+		t.Fatalf("Expected initial load to succeed, got %d", status)
+	}
+
+	e.upstream.FailWith("dates", http.StatusServiceUnavailable)
+	e.upstream.Malformed("relation", true)
+	if err := e.client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	status, body := e.get(t, "/artist/1")
+	if status != http.StatusOK {
+		t.Fatalf("Expected artist page to keep working, got %d", status)
+	}
+	if !strings.Contains(body, "Some data may be outdated") || !strings.Contains(body, "Osaka, Japan") {
+		t.Error("Expected outdated banner with last known concerts")
+	}
+}
+
+func TestEndToEndMalformedUpstream(t *testing.T) {
+	e := newEndToEnd(t)
+	e.upstream.Malformed(fakeupstream.All, true)
+
+	if status, _ := e.get(t, "/"); status != http.StatusInternalServerError {
+		t.Errorf("Expected 500 with no usable data, got %d", status)
+	}
+	if status, _ := e.get(t, "/api/health"); status != http.StatusServiceUnavailable {
+		t.Errorf("Expected health to report 503, got %d", status)
+	}
+
+	e.upstream.Malformed(fakeupstream.All, false)
+	if status, body := e.get(t, "/"); status != http.StatusOK || !strings.Contains(body, "Queen") {
+		t.Errorf("Expected recovery once upstream is fixed, got %d", status)
+	}
+}
+
+func TestEndToEndSlowUpstream(t *testing.T) {
+	e := newEndToEnd(t)
+	e.handler.RequestTimeout = 50 * time.Millisecond
+	e.upstream.SetLatency(fakeupstream.All, time.Second)
+
+	start := time.Now()
+	if status, _ := e.get(t, "/search?q=queen"); status != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for a slow upstream, got %d", status)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected request to be cut off by its deadline, took %v", elapsed)
+	}
+}
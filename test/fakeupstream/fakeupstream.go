@@ -0,0 +1,165 @@
+package fakeupstream
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const All = "*"
+
+var Endpoints = []string{"artists", "locations", "dates", "relation"}
+
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	bodies      map[string][]byte
+	latency     map[string]time.Duration
+	status      map[string]int
+	malformed   map[string]bool
+	requests    map[string]int
+	notModified int
+}
+
+func Start(t testing.TB, dir string) *Server {
+	t.Helper()
+
+	s := &Server{
+		bodies:    make(map[string][]byte),
+		latency:   make(map[string]time.Duration),
+		status:    make(map[string]int),
+		malformed: make(map[string]bool),
+		requests:  make(map[string]int),
+	}
+	for _, endpoint := range Endpoints {
+		body, err := os.ReadFile(filepath.Join(dir, endpoint+".json"))
+		if err != nil {
+			t.Fatalf("fakeupstream: %v", err)
+		}
+		s.bodies[endpoint] = body
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) BaseURL() string {
+	return s.URL + "/api"
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := path.Base(r.URL.Path)
+
+	s.mu.Lock()
+	body, ok := s.bodies[endpoint]
+	if !ok {
+		s.mu.Unlock()
+		http.NotFound(w, r)
+		return
+	}
+	s.requests[endpoint]++
+	latency := s.lookupDuration(endpoint)
+	status := s.lookupStatus(endpoint)
+	malformed := s.malformed[endpoint] || s.malformed[All]
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	if malformed {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body[:len(body)/2])
+		return
+	}
+
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum(body))
+	if r.Header.Get("If-None-Match") == etag {
+		s.mu.Lock()
+		s.notModified++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	w.Write(body)
+}
+
+func (s *Server) lookupDuration(endpoint string) time.Duration {
+	if d, ok := s.latency[endpoint]; ok {
+		return d
+	}
+	return s.latency[All]
+}
+
+func (s *Server) lookupStatus(endpoint string) int {
+	if status, ok := s.status[endpoint]; ok {
+		return status
+	}
+	return s.status[All]
+}
+
+func (s *Server) SetBody(endpoint string, body []byte) {
+	s.mu.Lock()
+	s.bodies[endpoint] = body
+	s.mu.Unlock()
+}
+
+func (s *Server) SetLatency(endpoint string, latency time.Duration) {
+	s.mu.Lock()
+	s.latency[endpoint] = latency
+	s.mu.Unlock()
+}
+
+func (s *Server) FailWith(endpoint string, status int) {
+	s.mu.Lock()
+	if status == 0 {
+		delete(s.status, endpoint)
+	} else {
+		s.status[endpoint] = status
+	}
+	s.mu.Unlock()
+}
+
+func (s *Server) Malformed(endpoint string, malformed bool) {
+	s.mu.Lock()
+	s.malformed[endpoint] = malformed
+	s.mu.Unlock()
+}
+
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if endpoint == All {
+		total := 0
+		for _, n := range s.requests {
+			total += n
+		}
+		return total
+	}
+	return s.requests[endpoint]
+}
+
+func (s *Server) NotModified() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notModified
+}
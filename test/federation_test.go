@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"groupie-tracker/internal/api"
+)
+
+func mirrorData() *api.APIData {
+	return &api.APIData{
+		Artists: []api.Artist{
+			{ID: 1, Name: "QUEEN", Members: []string{"Roger Meddows-Taylor", "freddie mercury", "Brian May", "John Daecon", "Mike Grose", "Barry Mitchell", "Doug Fogie"}, CreationDate: 1970, FirstAlbum: "14-12-1973"},
+			{ID: 2, Name: "Daft Punk", Members: []string{"Thomas Bangalter", "Guy-Manuel de Homem-Christo"}, CreationDate: 1993, FirstAlbum: "20-01-1997"},
+		},
+		Locations: api.LocationIndex{Index: []api.Location{
+			{ID: 1, Locations: []string{"paris-france"}},
+			{ID: 2, Locations: []string{"paris-france"}},
+		}},
+		Dates: api.DateIndex{Index: []api.Date{
+			{ID: 1, Dates: []string{"*01-01-2020"}},
+			{ID: 2, Dates: []string{"*14-07-2021"}},
+		}},
+		Relations: api.RelationIndex{Index: []api.Relation{
+			{ID: 1, DatesLocations: map[string][]string{"paris-france": {"01-01-2020"}}},
+			{ID: 2, DatesLocations: map[string][]string{"paris-france": {"14-07-2021"}}},
+		}},
+	}
+}
+
+func TestFederatedSource(t *testing.T) {
+	mirror := &flakySource{data: mirrorData()}
+	source := api.NewFederatedSource(
+		api.Upstream{Name: "primary", Source: api.NewFileSource(fixtureDir)},
+		api.Upstream{Name: "mirror", Source: mirror},
+	)
+	client := api.NewClient(source)
+
+	catalog, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if len(catalog.Artists()) != 9 {
+		t.Fatalf("Expected 9 artists after removing duplicates, got %d", len(catalog.Artists()))
+	}
+
+	queen, _ := catalog.Artist(1)
+	if queen.Name != "Queen" || queen.Source != "primary" {
+		t.Errorf("Expected Queen from primary, got %+v", queen)
+	}
+
+	daft, ok := catalog.Artist(10002)
+	if !ok || daft.Name != "Daft Punk" || daft.Source != "mirror" {
+		t.Fatalf("Expected Daft Punk from mirror with a remapped ID, got %+v", daft)
+	}
+	if tour := catalog.Tour(daft.ID); len(tour) != 1 || tour[0].Location.City != "Paris" {
+		t.Errorf("Unexpected tour for mirrored artist: %+v", tour)
+	}
+	for _, anomaly := range catalog.Anomalies() {
+		if anomaly.Field == "id" {
+			t.Errorf("Unexpected ID anomaly across upstreams: %+v", anomaly)
+		}
+	}
+
+	health := client.SourceHealth()
+	if len(health) != 2 || health[0].Artists != 8 || health[1].Artists != 1 || health[1].Duplicates != 1 {
+		t.Errorf("Unexpected source health: %+v", health)
+	}
+
+	mirror.setFail(true)
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh with one failing upstream failed: %v", err)
+	}
+	catalog, _ = client.Catalog()
+	if _, ok := catalog.Artist(10002); !ok {
+		t.Error("Expected last known data from the failing upstream to be kept")
+	}
+
+	health = client.SourceHealth()
+	if health[0].LastError != "" || health[1].LastError == "" || !health[1].Stale {
+		t.Errorf("Expected only the mirror to report an error, got %+v", health)
+	}
+	if !client.Degraded() {
+		t.Error("Expected client to report degraded data")
+	}
+}
+
+func TestFederatedSourceKeepsSnapshotOfDeadUpstream(t *testing.T) {
+	store := api.NewSnapshotStore(t.TempDir())
+	mirror := &flakySource{data: mirrorData()}
+	federated := func() *api.Client {
+		client := api.NewClient(api.NewFederatedSource(
+			api.Upstream{Name: "primary", Source: api.NewFileSource(fixtureDir)},
+			api.Upstream{Name: "mirror", Source: mirror},
+		))
+		client.UseSnapshots(store)
+		return client
+	}
+
+	if _, err := federated().Catalog(); err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	mirror.setFail(true)
+	restarted := federated()
+	if err := restarted.Refresh(); err != nil {
+		t.Fatalf("Refresh after restart failed: %v", err)
+	}
+
+	catalog, _ := restarted.Catalog()
+	daft, ok := catalog.Artist(10002)
+	if !ok || daft.Source != "mirror" || len(catalog.Tour(daft.ID)) != 1 {
+		t.Fatalf("Expected the dead mirror to keep its snapshot artists, got %+v", daft)
+	}
+	if changes, _ := restarted.Changes(0); len(changes) != 0 {
+		t.Errorf("Expected no changes for a dead upstream, got %+v", changes)
+	}
+	if health := restarted.SourceHealth(); !health[1].Stale || health[1].Artists != 1 {
+		t.Errorf("Expected stale mirror health, got %+v", health[1])
+	}
+
+	snapshot, err := store.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if len(snapshot.Data.Artists) != 9 {
+		t.Errorf("Expected snapshot to keep 9 artists, got %d", len(snapshot.Data.Artists))
+	}
+}
+
+func TestFederatedSourceMatchesFoldedNames(t *testing.T) {
+	source := api.NewFederatedSource(
+		api.Upstream{Name: "primary", Source: api.NewMemorySource(&api.APIData{Artists: []api.Artist{
+			{ID: 1, Name: "Beyoncé", Members: []string{"Beyoncé Knowles"}},
+			{ID: 10002, Name: "Big Number"},
+		}})},
+		api.Upstream{Name: "mirror", Source: api.NewMemorySource(&api.APIData{Artists: []api.Artist{
+			{ID: 1, Name: "Beyonce", Members: []string{"Beyonce Knowles"}},
+			{ID: 2, Name: "Daft Punk"},
+		}})},
+	)
+
+	data, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if len(data.Artists) != 2 {
+		t.Errorf("Expected 2 artists, got %+v", data.Artists)
+	}
+	if health := source.Health(); health[1].Duplicates != 1 || health[1].Collisions != 1 {
+		t.Errorf("Expected one duplicate and one collision from the mirror, got %+v", health[1])
+	}
+}
+
+func TestFederatedSourceAllFailing(t *testing.T) {
+	down := &flakySource{fail: true}
+	source := api.NewFederatedSource(
+		api.Upstream{Name: "a", Source: down},
+		api.Upstream{Name: "b", Source: down},
+	)
+	if _, err := source.Fetch(context.Background()); err == nil {
+		t.Error("Expected error when every upstream fails")
+	}
+}
+
+func TestOpenFederatedSource(t *testing.T) {
+	source, err := api.OpenSource("file", "main="+fixtureDir+", "+fixtureDir)
+	if err != nil {
+		t.Fatalf("OpenSource failed: %v", err)
+	}
+	federated, ok := source.(*api.FederatedSource)
+	if !ok || len(federated.Upstreams) != 2 || federated.Upstreams[0].Name != "main" || federated.Upstreams[1].Name != "upstream" {
+		t.Fatalf("Unexpected federated source: %+v", source)
+	}
+
+	data, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if len(data.Artists) != 8 {
+		t.Errorf("Expected identical upstreams to collapse to 8 artists, got %d", len(data.Artists))
+	}
+
+	if _, err := api.OpenSource("file", "a="+fixtureDir+",a="+fixtureDir); err == nil {
+		t.Error("Expected error for duplicate upstream names")
+	}
+}
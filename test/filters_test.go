@@ -16,7 +16,7 @@ func TestApplyFilters(t *testing.T) {
 		MembersMax:      100,
 	}
 
-	results, err := services.ApplyFilters(params)
+	results, err := newTestService().ApplyFilters(params)
 	if err != nil {
 		t.Fatalf("ApplyFilters failed: %v", err)
 	}
@@ -40,7 +40,7 @@ func TestMembersFilter(t *testing.T) {
 		MembersMax:      4,
 	}
 
-	results, err := services.ApplyFilters(params)
+	results, err := newTestService().ApplyFilters(params)
 	if err != nil {
 		t.Fatalf("ApplyFilters failed: %v", err)
 	}
@@ -1,31 +1,33 @@
 package test
 
 import (
+	"path/filepath"
 	"testing"
 
-	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/cassette"
 	"groupie-tracker/internal/services"
 )
 
 func TestGeocodeLocations(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping geocoding test in short mode")
+	recorder, err := cassette.New(filepath.Join("testdata", "cassettes", "nominatim.json"), cassette.ModeStrict)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
 	}
+	services.UseGeocodeTransport(recorder)
+	t.Cleanup(func() { services.UseGeocodeTransport(nil) })
 
-	relation, err := api.GetRelationByID(1)
+	catalog, err := newTestClient().Catalog()
 	if err != nil {
-		t.Fatalf("Failed to get relation: %v", err)
+		t.Fatalf("Failed to get catalog: %v", err)
 	}
 
-	locations, err := services.GeocodeLocations(relation)
+	locations, err := services.GeocodeLocations(catalog.Tour(1))
 	if err != nil {
 		t.Fatalf("GeocodeLocations failed: %v", err)
 	}
 
-	t.Logf("Geocoded %d locations", len(locations))
-
-	if len(locations) == 0 {
-		t.Log("Warning: No locations were successfully geocoded")
+	if len(locations) != 5 {
+		t.Fatalf("Expected 5 geocoded locations, got %d", len(locations))
 	}
 
 	for _, loc := range locations {
@@ -34,4 +36,8 @@ func TestGeocodeLocations(t *testing.T) {
 		}
 		t.Logf("Location: %s (%.4f, %.4f)", loc.Name, loc.Latitude, loc.Longitude)
 	}
+
+	if locations[0].Name != "Nagoya, Japan" || locations[0].Latitude < 35 || locations[0].Latitude > 35.5 {
+		t.Errorf("Unexpected first location: %+v", locations[0])
+	}
 }
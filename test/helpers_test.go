@@ -0,0 +1,28 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+)
+
+var fixtureDir = filepath.Join("testdata", "upstream")
+
+func newTestClient() *api.Client {
+	return api.NewClient(api.NewFileSource(fixtureDir))
+}
+
+func newTestService() *services.Service {
+	return services.New(newTestClient())
+}
+
+func loadFixture(t *testing.T) *api.APIData {
+	data, err := api.NewFileSource(fixtureDir).Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	return data
+}
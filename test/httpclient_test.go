@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/httpclient"
+)
+
+func testHTTPConfig() httpclient.Config {
+	return httpclient.Config{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryMin:         time.Millisecond,
+		RetryMax:         5 * time.Millisecond,
+		MaxBodySize:      1024,
+		BreakerThreshold: 2,
+		BreakerCooldown:  50 * time.Millisecond,
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	resp, err := httpclient.New(testHTTPConfig()).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(resp.Body) != "ok" || calls != 3 {
+		t.Errorf("Expected success on third attempt, got %q after %d calls", resp.Body, calls)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := httpclient.New(testHTTPConfig()).Get(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound || calls != 1 {
+		t.Errorf("Expected a single 404, got %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryOnlyNetworkErrors(t *testing.T) {
+	tests := []struct {
+		err   error
+		calls int32
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 3},
+		{errors.New("malformed response"), 1},
+	}
+	for _, tt := range tests {
+		var calls int32
+		client := httpclient.New(testHTTPConfig())
+		client.HTTP.Transport = transportFunc(func(*http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, tt.err
+		})
+		if _, err := client.Get(context.Background(), "http://upstream.invalid/artists", nil); err == nil {
+			t.Fatalf("Expected %v to fail", tt.err)
+		}
+		if calls != tt.calls {
+			t.Errorf("Expected %d calls for %v, got %d", tt.calls, tt.err, calls)
+		}
+	}
+}
+
+func TestBodySizeCap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	defer server.Close()
+
+	_, err := httpclient.New(testHTTPConfig()).Get(context.Background(), server.URL, nil)
+	if !errors.Is(err, httpclient.ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := testHTTPConfig()
+	config.MaxRetries = 0
+	client := httpclient.New(config)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		client.Get(ctx, server.URL, nil)
+	}
+
+	if _, err := client.Get(ctx, server.URL, nil); !errors.Is(err, httpclient.ErrCircuitOpen) {
+		t.Fatalf("Expected open circuit, got %v", err)
+	}
+	if status := client.Breakers(); len(status) != 1 || status[0].State != httpclient.StateOpen {
+		t.Errorf("Expected open breaker in status, got %+v", status)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err := client.Get(ctx, server.URL, nil); err != nil {
+		t.Fatalf("Expected half-open probe to succeed, got %v", err)
+	}
+	if status := client.Breakers(); status[0].State != httpclient.StateClosed {
+		t.Errorf("Expected breaker to close after probe, got %+v", status)
+	}
+}
+
+func TestCancelledProbeReleasesBreaker(t *testing.T) {
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := testHTTPConfig()
+	config.MaxRetries = 1
+	config.RetryMin = 100 * time.Millisecond
+	config.RetryMax = 100 * time.Millisecond
+	client := httpclient.New(config)
+
+	for i := 0; i < 2; i++ {
+		client.Get(context.Background(), server.URL, nil)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Get(ctx, server.URL, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected probe to stop at the deadline, got %v", err)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	if _, err := client.Get(context.Background(), server.URL, nil); err != nil {
+		t.Fatalf("Expected a new probe after cancellation, got %v", err)
+	}
+	if status := client.Breakers(); status[0].State != httpclient.StateClosed {
+		t.Errorf("Expected breaker to close after probe, got %+v", status)
+	}
+}
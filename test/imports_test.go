@@ -0,0 +1,151 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+)
+
+func TestLoadImports(t *testing.T) {
+	imports, err := api.LoadImports(filepath.Join("testdata", "imports"))
+	if err != nil {
+		t.Fatalf("LoadImports failed: %v", err)
+	}
+	if len(imports.Artists) != 3 {
+		t.Fatalf("Expected 3 imported artists, got %d", len(imports.Artists))
+	}
+
+	client := newTestClient()
+	client.UseImports(imports)
+	catalog, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	if len(catalog.Artists()) != 11 {
+		t.Errorf("Expected 11 artists after import, got %d", len(catalog.Artists()))
+	}
+
+	var owls *api.Artist
+	for _, artist := range catalog.Artists() {
+		if artist.ID < api.ImportIDBase && artist.Source != "" {
+			t.Errorf("Upstream artist %s has source %q", artist.Name, artist.Source)
+		}
+		if artist.Name == "The Night Owls" {
+			owls, _ = catalog.Artist(artist.ID)
+		}
+	}
+	if owls == nil {
+		t.Fatal("Expected The Night Owls in the catalog")
+	}
+	if owls.ID < api.ImportIDBase || owls.Source != "import/local-bands.csv" {
+		t.Errorf("Unexpected imported artist: %+v", owls)
+	}
+	if len(owls.Members) != 2 || owls.FirstAlbumDate.Year() != 2017 {
+		t.Errorf("Unexpected imported fields: %+v", owls)
+	}
+
+	tour := catalog.Tour(owls.ID)
+	if len(tour) != 2 || tour[0].Location.City != "Lisbon" || len(tour[0].Dates) != 2 {
+		t.Errorf("Unexpected imported tour: %+v", tour)
+	}
+
+	for _, anomaly := range catalog.Anomalies() {
+		if anomaly.ArtistID == owls.ID || anomaly.Field == "id" {
+			t.Errorf("Unexpected anomaly for imported data: %+v", anomaly)
+		}
+	}
+
+	service := services.New(client)
+	results, err := service.SearchArtists("mia chen")
+	if err != nil || len(results) != 1 || results[0].Name != "Velvet Static" {
+		t.Errorf("Expected search to find Velvet Static, got %v (%v)", results, err)
+	}
+
+	results, err = service.ApplyFilters(services.FilterParams{
+		CreationDateMin: 0,
+		CreationDateMax: 9999,
+		FirstAlbumMin:   0,
+		FirstAlbumMax:   9999,
+		MembersMin:      0,
+		MembersMax:      100,
+		Locations:       []string{"portugal"},
+	})
+	if err != nil || len(results) != 1 || results[0].Name != "The Night Owls" {
+		t.Errorf("Expected location filter to find The Night Owls, got %v (%v)", results, err)
+	}
+}
+
+func TestImportIDsAreStable(t *testing.T) {
+	imports, err := api.LoadImports(filepath.Join("testdata", "imports"))
+	if err != nil {
+		t.Fatalf("LoadImports failed: %v", err)
+	}
+
+	ids := make(map[string]int)
+	for _, artist := range imports.Merge(loadFixture(t)).Artists {
+		if artist.Source != "" {
+			ids[artist.Name] = artist.ID
+		}
+	}
+
+	data := loadFixture(t)
+	data.Artists = append(data.Artists,
+		api.Artist{ID: 250000, Name: "Far Away"},
+		api.Artist{ID: 250001, Name: "GARAGE KINGS"},
+	)
+	merged := imports.Merge(data)
+
+	var garage int
+	for _, artist := range merged.Artists {
+		if artist.Source == "" {
+			if artist.Name == "GARAGE KINGS" {
+				garage++
+			}
+			continue
+		}
+		if artist.Name == "Garage Kings" {
+			garage++
+		}
+		if ids[artist.Name] != artist.ID {
+			t.Errorf("Expected %s to keep ID %d, got %d", artist.Name, ids[artist.Name], artist.ID)
+		}
+	}
+	if len(ids) != 3 || garage != 1 {
+		t.Errorf("Expected an import matching an upstream artist to be skipped, got %+v", merged.Artists)
+	}
+}
+
+func TestImportValidation(t *testing.T) {
+	invalid := map[string]string{
+		"missing-column.csv": "name,members,creationDate\nBand,A,2000\n",
+		"bad-date.csv":       "name,members,creationDate,firstAlbum,image,location,date\nBand,A,2000,01-01-2001,,paris-france,31-31-2020\n",
+		"bad-location.csv":   "name,members,creationDate,firstAlbum,image,location,date\nBand,A,2000,01-01-2001,,Paris France,01-01-2020\n",
+		"no-members.json":    `{"artists": [{"name": "Band", "creationDate": 2000, "firstAlbum": "01-01-2001"}]}`,
+		"unknown-field.json": `{"artists": [{"name": "Band", "genre": "rock"}]}`,
+	}
+
+	for name, content := range invalid {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := api.LoadImports(dir); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+
+	dir := t.TempDir()
+	band := `{"artists": [{"name": "Band", "members": ["A"], "creationDate": 2000, "firstAlbum": "01-01-2001"}]}`
+	for _, name := range []string{"a.json", "b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(band), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := api.LoadImports(dir); err == nil {
+		t.Error("Expected error for an artist imported twice")
+	}
+}
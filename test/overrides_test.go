@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/internal/api"
+)
+
+func TestOverridesApply(t *testing.T) {
+	overrides, err := api.LoadOverrides(filepath.Join("testdata", "overrides.json"))
+	if err != nil {
+		t.Fatalf("LoadOverrides failed: %v", err)
+	}
+
+	client := newTestClient()
+	client.UseOverrides(overrides)
+	catalog, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	queen, _ := catalog.Artist(1)
+	if queen.Members[2] != "John Deacon" {
+		t.Errorf("Expected patched member, got %v", queen.Members)
+	}
+
+	scorpions, _ := catalog.Artist(4)
+	if scorpions.CreationDate != 1969 {
+		t.Errorf("Expected patched creation date, got %d", scorpions.CreationDate)
+	}
+
+	var london bool
+	for _, stop := range catalog.Tour(3) {
+		if stop.Location.Slug == "london-england-uk" && len(stop.Dates) == 2 {
+			london = true
+		}
+	}
+	if !london {
+		t.Errorf("Expected renamed London location, got %+v", catalog.Tour(3))
+	}
+
+	for _, anomaly := range catalog.Anomalies() {
+		if anomaly.ArtistID == 3 && anomaly.Field != "dates" {
+			t.Errorf("Expected location rename to keep indexes consistent, got %+v", anomaly)
+		}
+	}
+
+	stale := catalog.StalePatches()
+	if len(stale) != 2 {
+		t.Fatalf("Expected 2 stale patches, got %+v", stale)
+	}
+	if stale[0].Patch.ArtistID != 2 || stale[1].Patch.ArtistID != 42 {
+		t.Errorf("Unexpected stale patches: %+v", stale)
+	}
+
+	raw := loadFixture(t)
+	if raw.Artists[0].Members[2] != "John Daecon" {
+		t.Error("Expected overrides not to modify upstream data")
+	}
+}
+
+func TestOverridesValidation(t *testing.T) {
+	invalid := []string{
+		`{"patches": [{"artist": 1, "field": "genre", "value": "rock"}]}`,
+		`{"patches": [{"artist": 1, "field": "member", "value": "Someone"}]}`,
+		`{"patches": [{"artist": 1, "field": "creationDate", "value": "1970"}]}`,
+		`{"patches": [{"field": "name", "value": "Nobody"}]}`,
+		`{"patch": []}`,
+	}
+
+	for _, content := range invalid {
+		path := filepath.Join(t.TempDir(), "overrides.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := api.LoadOverrides(path); err == nil {
+			t.Errorf("Expected error for %s", content)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package test
+
+import (
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/httpclient"
+	"groupie-tracker/test/fakeupstream"
+)
+
+type fixtureServer struct {
+	*fakeupstream.Server
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	return &fixtureServer{fakeupstream.Start(t, fixtureDir)}
+}
+
+func (s *fixtureServer) setBody(name string, body []byte) {
+	s.SetBody(name, body)
+}
+
+func (s *fixtureServer) notModifiedCount() int {
+	return s.NotModified()
+}
+
+func (s *fixtureServer) fail(name string, failed bool) {
+	status := 0
+	if failed {
+		status = http.StatusInternalServerError
+	}
+	s.FailWith(name, status)
+}
+
+func (s *fixtureServer) client() *api.Client {
+	source := api.NewHTTPSource(s.URL)
+	config := testHTTPConfig()
+	config.MaxBodySize = 0
+	config.BreakerThreshold = 0
+	source.Client = httpclient.New(config)
+	return api.NewClient(source)
+}
+
+func TestPartialRefreshKeepsLastKnownGood(t *testing.T) {
+	server := newFixtureServer(t)
+	client := server.client()
+
+	if _, err := client.FetchAPI(); err != nil {
+		t.Fatalf("FetchAPI failed: %v", err)
+	}
+	if client.Degraded() {
+		t.Error("Expected healthy client after full fetch")
+	}
+
+	server.fail("dates", true)
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	catalog, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if !client.Degraded() || !catalog.Degraded() {
+		t.Error("Expected degraded catalog after partial refresh")
+	}
+	if len(catalog.Data.Dates.Index) == 0 {
+		t.Error("Expected dates carried over from previous snapshot")
+	}
+	section := catalog.Section("dates")
+	if section == nil || section.Error == "" || section.FetchedAt.IsZero() {
+		t.Errorf("Expected failed dates section with previous fetch time, got %+v", section)
+	}
+	if artists := catalog.Section("artists"); artists == nil || artists.Error != "" {
+		t.Errorf("Expected fresh artists section, got %+v", artists)
+	}
+
+	server.fail("dates", false)
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if client.Degraded() {
+		t.Error("Expected client to recover after full refresh")
+	}
+}
+
+func TestPartialFirstFetch(t *testing.T) {
+	server := newFixtureServer(t)
+	server.fail("relation", true)
+
+	catalog, err := server.client().Catalog()
+	if err != nil {
+		t.Fatalf("Expected partial catalog, got %v", err)
+	}
+	if len(catalog.Artists()) == 0 || !catalog.Degraded() {
+		t.Error("Expected artists with a degraded status")
+	}
+
+	server.fail("artists", true)
+	if _, err := server.client().Catalog(); err == nil {
+		t.Error("Expected error when artists cannot be loaded and nothing is cached")
+	}
+}
+
+func TestPartialRefreshWithOverlays(t *testing.T) {
+	imports, err := api.LoadImports(filepath.Join("testdata", "imports"))
+	if err != nil {
+		t.Fatalf("LoadImports failed: %v", err)
+	}
+	overrides, err := api.LoadOverrides(filepath.Join("testdata", "overrides.json"))
+	if err != nil {
+		t.Fatalf("LoadOverrides failed: %v", err)
+	}
+
+	server := newFixtureServer(t)
+	client := server.client()
+	client.UseImports(imports)
+	client.UseOverrides(overrides)
+	store := api.NewSnapshotStore(t.TempDir())
+	client.UseSnapshots(store)
+
+	before, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	server.fail("artists", true)
+	server.fail("relation", true)
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	after, err := client.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if len(after.Artists()) != len(before.Artists()) {
+		t.Errorf("Expected %d artists after partial refresh, got %d", len(before.Artists()), len(after.Artists()))
+	}
+	for _, artist := range after.Artists() {
+		if _, ok := before.Artist(artist.ID); !ok {
+			t.Errorf("Artist %s appeared as %d after partial refresh", artist.Name, artist.ID)
+		}
+	}
+	if !reflect.DeepEqual(after.StalePatches(), before.StalePatches()) {
+		t.Errorf("Expected stale patches %+v after partial refresh, got %+v", before.StalePatches(), after.StalePatches())
+	}
+	if changes, _ := client.Changes(0); len(changes) != 0 {
+		t.Errorf("Expected no changes after partial refresh, got %+v", changes)
+	}
+
+	snapshot, err := store.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if len(snapshot.Data.Artists) != len(loadFixture(t).Artists) {
+		t.Errorf("Expected snapshot of upstream artists only, got %d", len(snapshot.Data.Artists))
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+)
+
+func TestParseDateTolerant(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+		quirks   int
+		valid    bool
+	}{
+		{"23-08-2019", time.Date(2019, 8, 23, 0, 0, 0, 0, time.UTC), 0, true},
+		{"*23-08-2019", time.Date(2019, 8, 23, 0, 0, 0, 0, time.UTC), 1, true},
+		{" 3-8-2019", time.Date(2019, 8, 3, 0, 0, 0, 0, time.UTC), 2, true},
+		{"23/08/19", time.Date(2019, 8, 23, 0, 0, 0, 0, time.UTC), 2, true},
+		{"1973-12-14", time.Date(1973, 12, 14, 0, 0, 0, 0, time.UTC), 1, true},
+		{"31-02-2019", time.Time{}, 0, false},
+		{"sometime in 1999", time.Time{}, 0, false},
+		{"*", time.Time{}, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			date, quirks, err := api.ParseDateTolerant(tt.input)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ParseDateTolerant(%q) error = %v, want valid=%v", tt.input, err, tt.valid)
+			}
+			if !date.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, date)
+			}
+			if len(quirks) != tt.quirks {
+				t.Errorf("Expected %d quirks, got %v", tt.quirks, quirks)
+			}
+		})
+	}
+}
+
+func TestDataQualityReport(t *testing.T) {
+	report, err := newTestService().DataQuality()
+	if err != nil {
+		t.Fatalf("DataQuality failed: %v", err)
+	}
+
+	if report.Total == 0 {
+		t.Fatal("Expected anomalies for asterisk dates in the fixture")
+	}
+
+	var found bool
+	for _, artist := range report.Artists {
+		if artist.Artist != "XXXTentacion" {
+			continue
+		}
+		for _, field := range artist.Fields {
+			if field.Field == "firstAlbum" && field.Anomalies[0].Problem == api.QuirkUnpadded {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected unpadded firstAlbum anomaly for XXXTentacion")
+	}
+
+	catalog, _ := newTestClient().Catalog()
+	artist, _ := catalog.Artist(5)
+	if artist.FirstAlbumDate.Year() != 2017 {
+		t.Errorf("Expected repaired first album year 2017, got %d", artist.FirstAlbumDate.Year())
+	}
+}
+
+func TestBuildQualityReportGrouping(t *testing.T) {
+	report := services.BuildQualityReport([]api.Anomaly{
+		{ArtistID: 2, Artist: "B", Field: "dates", Value: "x", Problem: "invalid"},
+		{ArtistID: 1, Artist: "A", Field: "firstAlbum", Value: "*1", Problem: api.QuirkAsterisk, Repaired: true},
+		{ArtistID: 1, Artist: "A", Field: "dates", Value: "*2", Problem: api.QuirkAsterisk, Repaired: true},
+	})
+
+	if report.Total != 3 || report.Unrepaired != 1 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if len(report.Artists) != 2 || report.Artists[0].ArtistID != 1 {
+		t.Fatalf("Expected artists grouped by ID, got %+v", report.Artists)
+	}
+	if len(report.Artists[0].Fields) != 2 || report.Artists[0].Fields[0].Field != "dates" {
+		t.Errorf("Expected fields grouped and sorted, got %+v", report.Artists[0].Fields)
+	}
+}
@@ -0,0 +1,327 @@
+package test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"groupie-tracker/internal/services"
+)
+
+func searchNames(t *testing.T, svc *services.Service, query string) string {
+	t.Helper()
+	results, err := svc.SearchArtists(query)
+	if err != nil {
+		t.Fatalf("SearchArtists(%q) failed: %v", query, err)
+	}
+	names := make([]string, len(results))
+	for i, artist := range results {
+		names[i] = artist.Name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func TestQueryLanguage(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"member:freddie", "Queen"},
+		{`member:"freddie mercury"`, "Queen"},
+		{`"freddie mercury"`, "Queen"},
+		{"location:usa created:1970..1980", "Queen"},
+		{"location:new_zealand", "Gorillaz, Queen"},
+		{`location:"new zealand" -name:queen`, "Gorillaz"},
+		{"location:sao_paulo", "Guns N' Roses, Pink Floyd, SOJA"},
+		{"album:>2000", "Gorillaz, Mac Miller, SOJA, XXXTentacion"},
+		{"album:<=1967", "Pink Floyd"},
+		{"created:1965", "Pink Floyd, Scorpions"},
+		{"created:..1970", "Pink Floyd, Queen, Scorpions"},
+		{"created:2000..", "Mac Miller, XXXTentacion"},
+		{"album:12-1973", "Queen"},
+		{"name:queen OR name:gorillaz", "Gorillaz, Queen"},
+		{"location:uk AND NOT name:pink", "Gorillaz"},
+		{"(member:slash OR member:syd) created:1985", "Guns N' Roses"},
+		{"Location:Paris", "Scorpions"},
+		{"guns-n", "Guns N' Roses"},
+	}
+
+	svc := newTestService()
+	for _, tt := range tests {
+		if got := searchNames(t, svc, tt.query); got != tt.expected {
+			t.Errorf("Query %q: expected [%s], got [%s]", tt.query, tt.expected, got)
+		}
+	}
+}
+
+func TestQueryParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"genre:rock", 0},
+		{`member:"freddie`, 7},
+		{"(name:queen", 0},
+		{"name:queen)", 10},
+		{"queen OR", 8},
+		{"created:nineties", 0},
+		{"created:1990..1980", 0},
+		{"member:", 0},
+		{"()", 1},
+		{"   ", 3},
+		{"'", 0},
+		{"queen !!", 6},
+		{`location:"- -"`, 0},
+	}
+
+	for _, tt := range tests {
+		_, err := services.ParseQuery(tt.query)
+		var queryErr *services.QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("Query %q: expected QueryError, got %v", tt.query, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos {
+			t.Errorf("Query %q: expected error at %d, got %d (%v)", tt.query, tt.pos, queryErr.Pos, err)
+		}
+	}
+
+	if _, err := newTestService().SearchArtists("genre:rock"); err == nil {
+		t.Error("Expected SearchArtists to return the parse error")
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	svc := newTestService()
+
+	results, err := svc.Search("ma")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	var order []string
+	for _, result := range results {
+		order = append(order, result.Artist.Name)
+	}
+	if len(order) < 2 || order[0] != "Mac Miller" {
+		t.Errorf("Expected name prefix match first, got %v", order)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("Results not sorted by score: %v", order)
+		}
+	}
+
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{"queen", "Queen", ""},
+		{"scorpions OR member:klaus", "Scorpions", ""},
+		{"s", "SOJA", "Pink Floyd"},
+		{"slash OR 1985", "Guns N' Roses", ""},
+	}
+	for _, tt := range tests {
+		results, err := svc.Search(tt.query)
+		if err != nil || len(results) == 0 {
+			t.Fatalf("Search(%q) failed: %v", tt.query, err)
+		}
+		if results[0].Artist.Name != tt.better {
+			t.Errorf("Search(%q): expected %s first, got %s", tt.query, tt.better, results[0].Artist.Name)
+		}
+	}
+}
+
+func TestSearchMatchDetails(t *testing.T) {
+	svc := newTestService()
+
+	results, err := svc.Search("freddie")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected one result, got %v (%v)", results, err)
+	}
+	if results[0].NameMatch() != nil {
+		t.Error("Expected no name match for a member query")
+	}
+	matches := results[0].OtherMatches()
+	if len(matches) != 1 || matches[0].Field != services.FieldMember || matches[0].Value != "Freddie Mercury" {
+		t.Fatalf("Unexpected matches: %+v", matches)
+	}
+	if spans := matches[0].Spans; len(spans) != 1 || spans[0] != (services.Span{Start: 0, End: 7}) {
+		t.Errorf("Unexpected spans: %+v", spans)
+	}
+
+	results, err = svc.Search(`name:e location:"new zealand"`)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected one result, got %v (%v)", results, err)
+	}
+	name := results[0].NameMatch()
+	if name == nil {
+		t.Fatal("Expected name match for Queen")
+	}
+	var parts []string
+	for _, part := range name.Parts() {
+		if part.Match {
+			parts = append(parts, "["+part.Text+"]")
+		} else {
+			parts = append(parts, part.Text)
+		}
+	}
+	if got := strings.Join(parts, ""); got != "Qu[ee]n" {
+		t.Errorf("Unexpected highlighting %q", got)
+	}
+
+	var location bool
+	for _, m := range results[0].OtherMatches() {
+		if m.Field == services.FieldLocation && strings.HasSuffix(m.Value, "New Zealand") && len(m.Spans) == 1 {
+			location = true
+		}
+	}
+	if !location {
+		t.Errorf("Expected highlighted location match: %+v", results[0].Matches)
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	svc := newTestService()
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"quen", "Queen"},
+		{"scorpoins", "Scorpions"},
+		{"member:mercuri", "Queen"},
+		{"freddy mercuri", "Queen"},
+		{"location:pitsburgh", "Mac Miller"},
+		{"location:oklahomma", "SOJA"},
+		{"qu", "Queen"},
+		{"1956", ""},
+	}
+	for _, tt := range tests {
+		if got := searchNames(t, svc, tt.query); got != tt.expected {
+			t.Errorf("Query %q: expected [%s], got [%s]", tt.query, tt.expected, got)
+		}
+	}
+
+	results, err := svc.Search("miler")
+	if err != nil || len(results) == 0 {
+		t.Fatalf("Search failed: %v (%v)", results, err)
+	}
+	if results[0].Artist.Name != "Mac Miller" || !results[0].Matches[0].Fuzzy {
+		t.Errorf("Expected a fuzzy match on Mac Miller, got %+v", results[0])
+	}
+
+	exact, err := svc.Search("roger")
+	if err != nil || len(exact) == 0 {
+		t.Fatalf("Search failed: %v (%v)", exact, err)
+	}
+	fuzzy, err := svc.Search("rogar")
+	if err != nil || len(fuzzy) == 0 {
+		t.Fatalf("Search failed: %v (%v)", fuzzy, err)
+	}
+	if fuzzy[0].Score >= exact[0].Score {
+		t.Errorf("Expected fuzzy match to score below exact match: %d >= %d", fuzzy[0].Score, exact[0].Score)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	svc := newTestService()
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"qeeun", "queen"},
+		{"member:fredy", ""},
+		{"member:fraddey", "member:freddie"},
+		{`"pnik flyod"`, `"pink floyd"`},
+		{"qeeun -name:gorillaz", "queen -name:gorillaz"},
+		{"queen", ""},
+		{"zzzzzz", ""},
+	}
+	for _, tt := range tests {
+		got, err := svc.DidYouMean(tt.query)
+		if err != nil {
+			t.Fatalf("DidYouMean(%q) failed: %v", tt.query, err)
+		}
+		if got != tt.expected {
+			t.Errorf("DidYouMean(%q): expected %q, got %q", tt.query, tt.expected, got)
+		}
+	}
+}
+
+func TestSearchConcerts(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"osaka", "Queen"},
+		{"london", "Gorillaz, Pink Floyd"},
+		{"sao paulo", "Guns N' Roses, Pink Floyd, SOJA"},
+		{"08-2019", "Queen, SOJA"},
+		{"23-08-2019", "Queen"},
+		{"date:2020", "Queen, Scorpions"},
+		{"date:<2019", "Mac Miller, XXXTentacion"},
+		{"date:2018..2018 location:pittsburgh", "Mac Miller"},
+		{"germany date:2019", ""},
+		{"location:usa -date:2019..2020", "Mac Miller, XXXTentacion"},
+	}
+
+	svc := newTestService()
+	for _, tt := range tests {
+		if got := searchNames(t, svc, tt.query); got != tt.expected {
+			t.Errorf("Query %q: expected [%s], got [%s]", tt.query, tt.expected, got)
+		}
+	}
+
+	if _, err := services.ParseQuery("date:soon"); err == nil {
+		t.Error("Expected an error for date:soon")
+	}
+
+	results, err := svc.Search("08-2019")
+	if err != nil || len(results) == 0 {
+		t.Fatalf("Search failed: %v (%v)", results, err)
+	}
+	m := results[0].Matches[0]
+	if m.Field != services.FieldDate || m.Label() != "concert date" || !strings.Contains(m.Value, " in ") {
+		t.Fatalf("Unexpected match %+v", m)
+	}
+	if span := m.Spans[0]; m.Value[span.Start:span.End] != "08-2019" {
+		t.Errorf("Expected month and year highlighted, got %q", m.Value[span.Start:span.End])
+	}
+}
+
+func TestConcertSuggestions(t *testing.T) {
+	svc := newTestService()
+
+	tests := []struct {
+		query string
+		want  services.Suggestion
+	}{
+		{"osa", services.Suggestion{Text: "Osaka, Japan", Type: "location"}},
+		{"new zea", services.Suggestion{Text: "Dunedin, New Zealand", Type: "location"}},
+		{"23-08", services.Suggestion{Text: "23-08-2019", Type: "concert date"}},
+		{"23.08.2019", services.Suggestion{Text: "23-08-2019", Type: "concert date"}},
+	}
+	for _, tt := range tests {
+		suggestions, err := svc.GetSuggestions(tt.query)
+		if err != nil {
+			t.Fatalf("GetSuggestions(%q) failed: %v", tt.query, err)
+		}
+		found := false
+		for _, s := range suggestions {
+			found = found || (s.Text == tt.want.Text && s.Type == tt.want.Type)
+		}
+		if !found {
+			t.Errorf("GetSuggestions(%q): expected %+v in %+v", tt.query, tt.want, suggestions)
+		}
+	}
+
+	dashed, _ := svc.GetSuggestions("08-2019")
+	dotted, _ := svc.GetSuggestions("08.2019")
+	if len(dashed) == 0 || len(dotted) != len(dashed) {
+		t.Errorf("Expected 08.2019 to suggest the same as 08-2019, got %d and %d", len(dotted), len(dashed))
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"groupie-tracker/internal/api"
+)
+
+func hasAnomaly(anomalies []api.Anomaly, id int, problem string) bool {
+	for _, anomaly := range anomalies {
+		if anomaly.ArtistID == id && anomaly.Problem == problem {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReconcileCleanFixture(t *testing.T) {
+	_, anomalies := api.Reconcile(loadFixture(t))
+	if len(anomalies) != 0 {
+		t.Errorf("Expected fixture indexes to agree, got %+v", anomalies)
+	}
+}
+
+func TestReconcileRebuildsMissingRelation(t *testing.T) {
+	data := loadFixture(t)
+	original := data.Relations.Index[1]
+	data.Relations.Index = append(data.Relations.Index[:1], data.Relations.Index[2:]...)
+
+	reconciled, anomalies := api.Reconcile(data)
+
+	if !hasAnomaly(anomalies, 2, "no relation record, rebuilt from locations and dates") {
+		t.Errorf("Expected rebuilt relation anomaly, got %+v", anomalies)
+	}
+
+	var rebuilt *api.Relation
+	for i := range reconciled.Relations.Index {
+		if reconciled.Relations.Index[i].ID == 2 {
+			rebuilt = &reconciled.Relations.Index[i]
+		}
+	}
+	if rebuilt == nil {
+		t.Fatal("Expected relation 2 to be rebuilt")
+	}
+	if !reflect.DeepEqual(rebuilt.DatesLocations, original.DatesLocations) {
+		t.Errorf("Rebuilt relation %v differs from original %v", rebuilt.DatesLocations, original.DatesLocations)
+	}
+
+	catalog := api.NewCatalog(data)
+	if len(catalog.ConcertsByArtist(2)) != 5 {
+		t.Errorf("Expected catalog to use rebuilt relation, got %d concerts", len(catalog.ConcertsByArtist(2)))
+	}
+}
+
+func TestReconcileFlagsMismatches(t *testing.T) {
+	data := loadFixture(t)
+	data.Dates.Index[2].Dates = append(data.Dates.Index[2].Dates, "01-01-2021")
+	data.Locations.Index[0].Locations = append(data.Locations.Index[0].Locations, "tokyo-japan")
+	data.Artists = append(data.Artists, api.Artist{ID: 12, Name: "Ghost"})
+
+	_, anomalies := api.Reconcile(data)
+
+	checks := []struct {
+		id      int
+		problem string
+	}{
+		{3, "date with no location"},
+		{1, "location with no dates"},
+		{10, "ID gap"},
+		{12, "no relation record"},
+		{12, "no dates record"},
+	}
+	for _, check := range checks {
+		if !hasAnomaly(anomalies, check.id, check.problem) {
+			t.Errorf("Expected %q for artist %d", check.problem, check.id)
+		}
+	}
+}
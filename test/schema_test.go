@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"groupie-tracker/internal/api"
+)
+
+func TestCheckSchemaFixture(t *testing.T) {
+	for _, section := range []string{"artists", "locations", "dates", "relation"} {
+		body, err := os.ReadFile(filepath.Join(fixtureDir, section+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		warnings, err := api.CheckSchema(section, body)
+		if err != nil {
+			t.Fatalf("CheckSchema(%s) failed: %v", section, err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("Expected no drift in fixture %s, got %+v", section, warnings)
+		}
+	}
+}
+
+func TestCheckSchemaDrift(t *testing.T) {
+	body := []byte(`{"index":[{"id":1,"dates":["*01-01-2020"],"venue":"Stadium"},{"id":"2","dates":["02-01-2020"]},{"dates":[]}]}`)
+	warnings, err := api.CheckSchema("dates", body)
+	if err != nil {
+		t.Fatalf("CheckSchema failed: %v", err)
+	}
+
+	expected := map[string]api.DriftWarning{
+		"venue":                  {Section: "dates", Field: "venue", Kind: api.DriftAdded, Actual: "string", Records: 1, Total: 3},
+		"id/" + api.DriftType:    {Section: "dates", Field: "id", Kind: api.DriftType, Expected: "number", Actual: "string", Records: 1, Total: 3},
+		"id/" + api.DriftMissing: {Section: "dates", Field: "id", Kind: api.DriftMissing, Records: 1, Total: 3},
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %+v", len(expected), warnings)
+	}
+	for _, w := range warnings {
+		key := w.Field
+		if w.Field == "id" {
+			key += "/" + w.Kind
+		}
+		if w != expected[key] {
+			t.Errorf("Unexpected warning %+v, want %+v", w, expected[key])
+		}
+	}
+
+	if warnings, _ := api.CheckSchema("relation", []byte(`[]`)); len(warnings) != 1 || warnings[0].Kind != api.DriftType {
+		t.Errorf("Expected shape change for relation, got %+v", warnings)
+	}
+	for section, body := range map[string]string{"artists": `[]`, "dates": `{"index":[]}`} {
+		if warnings, _ := api.CheckSchema(section, []byte(body)); len(warnings) != 1 || warnings[0].Kind != api.DriftEmpty {
+			t.Errorf("Expected empty %s to be reported, got %+v", section, warnings)
+		}
+	}
+}
+
+func TestDriftKeepsGoodCache(t *testing.T) {
+	server := newFixtureServer(t)
+	client := server.client()
+
+	if _, err := client.Catalog(); err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(fixtureDir, "artists.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.setBody("artists", []byte(strings.ReplaceAll(string(body), `"name"`, `"title"`)))
+
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if status := client.Status(); !strings.Contains(status.LastError, "schema drift") {
+		t.Errorf("Expected drifted artists section to be rejected, got %q", status.LastError)
+	}
+	artist, err := client.GetArtistByID(1)
+	if err != nil || artist.Name != "Queen" {
+		t.Errorf("Expected good cache to be kept, got %v, %v", artist, err)
+	}
+	if !client.Degraded() {
+		t.Error("Expected client to report degraded data")
+	}
+
+	drift := client.SchemaDrift()
+	var missing, added bool
+	for _, w := range drift {
+		missing = missing || (w.Field == "name" && w.Kind == api.DriftMissing)
+		added = added || (w.Field == "title" && w.Kind == api.DriftAdded)
+	}
+	if !missing || !added {
+		t.Errorf("Expected drift warnings for name and title, got %+v", drift)
+	}
+
+	server.setBody("artists", []byte(strings.Replace(string(body), `"id": 1,`, `"id": 1, "genre": "rock",`, 1)))
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Expected added field to be accepted, got %v", err)
+	}
+	if drift := client.SchemaDrift(); len(drift) != 1 || drift[0].Field != "genre" {
+		t.Errorf("Expected only the added field to be reported, got %+v", drift)
+	}
+
+	for _, broken := range []string{`[]`, strings.Replace(string(body), `"id": 1,`, `"id": 1.5,`, 1), string(body) + `]`} {
+		server.setBody("artists", []byte(broken))
+		if err := client.Refresh(); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if status := client.Status(); status.LastError == "" {
+			t.Errorf("Expected artists %.20q to be rejected", broken)
+		}
+		if artist, err := client.GetArtistByID(1); err != nil || artist.Name != "Queen" {
+			t.Errorf("Expected good cache to be kept, got %v, %v", artist, err)
+		}
+	}
+}
+
+func TestDriftThreshold(t *testing.T) {
+	defer func(threshold float64) { api.DriftThreshold = threshold }(api.DriftThreshold)
+
+	dir := t.TempDir()
+	for _, section := range []string{"artists", "locations", "dates", "relation"} {
+		body, err := os.ReadFile(filepath.Join(fixtureDir, section+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if section == "artists" {
+			body = []byte(strings.Replace(string(body), `"image": `, `"picture": `, 1))
+		}
+		if err := os.WriteFile(filepath.Join(dir, section+".json"), body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api.DriftThreshold = 0.2
+	if _, err := api.NewFileSource(dir).Fetch(context.Background()); err != nil {
+		t.Errorf("Expected 1/8 missing images to stay under the threshold, got %v", err)
+	}
+
+	api.DriftThreshold = 0.1
+	if _, err := api.NewFileSource(dir).Fetch(context.Background()); err == nil {
+		t.Error("Expected 1/8 missing images to exceed the threshold")
+	}
+}
@@ -2,8 +2,6 @@ package test
 
 import (
 	"testing"
-
-	"groupie-tracker/internal/services"
 )
 
 func TestSearchArtists(t *testing.T) {
@@ -17,9 +15,10 @@ func TestSearchArtists(t *testing.T) {
 		{"nonexistent", false},
 	}
 
+	svc := newTestService()
 	for _, tt := range tests {
 		t.Run(tt.query, func(t *testing.T) {
-			results, err := services.SearchArtists(tt.query)
+			results, err := svc.SearchArtists(tt.query)
 			if err != nil {
 				t.Fatalf("SearchArtists failed: %v", err)
 			}
@@ -35,14 +34,15 @@ func TestSearchArtists(t *testing.T) {
 }
 
 func TestGetSuggestions(t *testing.T) {
-	suggestions, err := services.GetSuggestions("qu")
+	svc := newTestService()
+	suggestions, err := svc.GetSuggestions("qu")
 	if err != nil {
 		t.Fatalf("GetSuggestions failed: %v", err)
 	}
 
 	t.Logf("Got %d suggestions for 'qu'", len(suggestions))
 
-	emptySuggestions, err := services.GetSuggestions("")
+	emptySuggestions, err := svc.GetSuggestions("")
 	if err != nil {
 		t.Fatalf("GetSuggestions with empty query failed: %v", err)
 	}
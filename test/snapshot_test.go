@@ -0,0 +1,93 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"groupie-tracker/internal/api"
+)
+
+func TestSnapshotSaveAndLatest(t *testing.T) {
+	dir := t.TempDir()
+	store := api.NewSnapshotStore(dir)
+	store.Keep = 2
+
+	data := loadFixture(t)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := store.Save(data, start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "snapshot-v*.json"))
+	if len(files) != 2 {
+		t.Errorf("Expected 2 snapshots after pruning, got %d", len(files))
+	}
+
+	snapshot, err := store.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if !snapshot.FetchedAt.Equal(start.Add(2 * time.Second).UTC()) {
+		t.Errorf("Expected newest snapshot, got one fetched at %v", snapshot.FetchedAt)
+	}
+	if len(snapshot.Data.Artists) != len(data.Artists) {
+		t.Errorf("Expected %d artists, got %d", len(data.Artists), len(snapshot.Data.Artists))
+	}
+}
+
+func TestSnapshotSkipsCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := api.NewSnapshotStore(dir)
+
+	data := loadFixture(t)
+
+	fetchedAt := time.Now()
+	if err := store.Save(data, fetchedAt); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	corrupt := filepath.Join(dir, "snapshot-v1-99999999999999999.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Latest(); err != nil {
+		t.Fatalf("Expected to fall back to valid snapshot, got %v", err)
+	}
+}
+
+func TestClientBootsFromSnapshot(t *testing.T) {
+	store := api.NewSnapshotStore(t.TempDir())
+
+	source := newFlakySource(t)
+	online := api.NewClient(source)
+	online.UseSnapshots(store)
+	if _, err := online.FetchAPI(); err != nil {
+		t.Fatalf("FetchAPI failed: %v", err)
+	}
+
+	source.setFail(true)
+	restarted := api.NewClient(source)
+	if err := restarted.UseSnapshots(store); err != nil {
+		t.Fatalf("UseSnapshots failed: %v", err)
+	}
+	data, err := restarted.FetchAPI()
+	if err != nil {
+		t.Fatalf("Expected data from snapshot, got error: %v", err)
+	}
+	if len(data.Artists) == 0 {
+		t.Error("Expected artists from snapshot")
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected no upstream call after restart, got %d calls", source.calls)
+	}
+
+	offline := api.NewClient(api.NewSnapshotSource(store))
+	if _, err := offline.GetArtistByID(1); err != nil {
+		t.Errorf("Offline client failed: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"groupie-tracker/internal/services"
+)
+
+func suggestionIndex(suggestions []services.Suggestion, text string) int {
+	for i, s := range suggestions {
+		if s.Text == text {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestSuggestionRanking(t *testing.T) {
+	svc := newTestService()
+
+	suggestions, err := svc.GetSuggestions("ma")
+	if err != nil {
+		t.Fatalf("GetSuggestions failed: %v", err)
+	}
+	if len(suggestions) == 0 || suggestions[0].Text != "Mac Miller" {
+		t.Fatalf("Expected the artist prefix match first, got %+v", suggestions)
+	}
+	prefix, wordPrefix := suggestionIndex(suggestions, "Matthias Jabs"), suggestionIndex(suggestions, "Brian May")
+	if prefix < 0 || wordPrefix < 0 || prefix > wordPrefix {
+		t.Errorf("Expected prefix matches before word matches, got %+v", suggestions)
+	}
+	if infix := suggestionIndex(suggestions, "Saitama, Japan"); infix >= 0 && infix < wordPrefix {
+		t.Errorf("Expected infix matches last, got %+v", suggestions)
+	}
+}
+
+func TestSuggestionQuota(t *testing.T) {
+	svc := newTestService()
+
+	suggestions, err := svc.GetSuggestions("a")
+	if err != nil {
+		t.Fatalf("GetSuggestions failed: %v", err)
+	}
+	if len(suggestions) != services.DefaultSuggestionLimit {
+		t.Fatalf("Expected %d suggestions, got %d", services.DefaultSuggestionLimit, len(suggestions))
+	}
+	counts := make(map[string]int)
+	for _, s := range suggestions {
+		counts[s.Type]++
+	}
+	if counts["artist/band"] == 0 || counts["member"] == 0 || counts["location"] == 0 {
+		t.Errorf("Expected every type to be represented, got %v", counts)
+	}
+	for kind, n := range counts {
+		if n > 4 {
+			t.Errorf("Type %s exceeds its quota: %v", kind, counts)
+		}
+	}
+
+	suggestions, err = svc.Suggest("a", services.SuggestionOptions{Limit: 3})
+	if err != nil || len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %+v (%v)", suggestions, err)
+	}
+	if suggestions[0].Type == suggestions[1].Type || suggestions[1].Type == suggestions[2].Type || suggestions[0].Type == suggestions[2].Type {
+		t.Errorf("Expected three different types, got %+v", suggestions)
+	}
+
+	suggestions, err = svc.Suggest("a", services.SuggestionOptions{Limit: 20, Types: []string{services.FieldMember}})
+	if err != nil || len(suggestions) == 0 {
+		t.Fatalf("Expected member suggestions, got %+v (%v)", suggestions, err)
+	}
+	for _, s := range suggestions {
+		if s.Type != "member" {
+			t.Errorf("Expected only members, got %+v", s)
+		}
+	}
+}
+
+func TestSuggestionLinks(t *testing.T) {
+	svc := newTestService()
+
+	suggestions, err := svc.GetSuggestions("queen")
+	if err != nil || len(suggestions) == 0 {
+		t.Fatalf("GetSuggestions failed: %+v (%v)", suggestions, err)
+	}
+	if s := suggestions[0]; s.ID != 1 || s.URL != "/artist/1" || s.Artists != 1 {
+		t.Errorf("Expected a link to Queen, got %+v", s)
+	}
+
+	suggestions, err = svc.GetSuggestions("london")
+	if err != nil || len(suggestions) != 1 {
+		t.Fatalf("GetSuggestions failed: %+v (%v)", suggestions, err)
+	}
+	s := suggestions[0]
+	if s.Artists != 2 || s.ID != 0 || !strings.HasPrefix(s.URL, "/search?q=") {
+		t.Fatalf("Expected a search link for a shared location, got %+v", s)
+	}
+	link, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := searchNames(t, svc, link.Query().Get("q")); got != "Gorillaz, Pink Floyd" {
+		t.Errorf("Expected the search link to find both artists, got [%s]", got)
+	}
+}
+
+func TestParseSuggestionOptions(t *testing.T) {
+	opts, err := services.ParseSuggestionOptions("", "")
+	if err != nil || opts.Limit != services.DefaultSuggestionLimit || opts.Types != nil {
+		t.Errorf("Unexpected defaults %+v (%v)", opts, err)
+	}
+
+	opts, err = services.ParseSuggestionOptions("500", "artist, Location,artist")
+	if err != nil || opts.Limit != services.MaxSuggestionLimit || strings.Join(opts.Types, ",") != "name,location" {
+		t.Errorf("Unexpected options %+v (%v)", opts, err)
+	}
+
+	for _, tt := range [][2]string{{"0", ""}, {"ten", ""}, {"", "genre"}} {
+		if _, err := services.ParseSuggestionOptions(tt[0], tt[1]); err == nil {
+			t.Errorf("Expected an error for limit=%q types=%q", tt[0], tt[1])
+		}
+	}
+}
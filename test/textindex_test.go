@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+	"groupie-tracker/internal/textindex"
+)
+
+func TestTextIndex(t *testing.T) {
+	index := textindex.New()
+	index.Add("name", "Queen", 1)
+	index.Add("member", "Freddie Mercury", 1)
+	index.Add("location", "Dunedin, New Zealand", 1, "dunedin-new_zealand")
+	index.Add("location", "Dunedin, New Zealand", 2)
+	index.Add("name", "Gorillaz", 2)
+	index.Add("name", "", 3)
+
+	texts := func(entries []int) []string {
+		var result []string
+		for _, i := range entries {
+			result = append(result, index.Entry(i).Text)
+		}
+		return result
+	}
+
+	tests := []struct {
+		query    string
+		fields   []string
+		expected []string
+	}{
+		{"que", nil, []string{"Queen"}},
+		{"ee", nil, []string{"Queen"}},
+		{"e", []string{"name"}, []string{"Queen"}},
+		{"zeal new", nil, []string{"Dunedin, New Zealand"}},
+		{"new_zealand", []string{"location"}, []string{"Dunedin, New Zealand"}},
+		{"MERC", nil, []string{"Freddie Mercury"}},
+		{"r", []string{"name", "member"}, []string{"Freddie Mercury", "Gorillaz"}},
+		{"xyz", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := texts(index.Search(tt.query, tt.fields...)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Search(%q, %v): expected %v, got %v", tt.query, tt.fields, tt.expected, got)
+		}
+	}
+
+	if got := index.Entry(index.Search("dunedin")[0]).IDs; !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Expected both artists on the shared location, got %v", got)
+	}
+	if got := index.Words("location"); !reflect.DeepEqual(got, []string{"dunedin", "new", "zealand"}) {
+		t.Errorf("Unexpected location words %v", got)
+	}
+	if index.Len() != 4 {
+		t.Errorf("Expected 4 entries, got %d", index.Len())
+	}
+}
+
+func TestSearchIndexMatchesScan(t *testing.T) {
+	client := newTestClient()
+	svc := services.New(client)
+	catalog, err := client.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := []string{
+		"queen", "ee", "a", "freddy mercuri", `"pnik flyod"`, "guns-n", "n'", "1985", "98",
+		"12-1973", "08-2019", "8/2019", "08.2019", "date:1.8.2019", "date:2018..2019", "album:>2000", "created:..1970",
+		"location:new_zealand", "sao paulo", "-name:queen", "NOT (usa OR uk)", "member:slash OR osaka",
+		"location:pitsburgh", "seattle",
+	}
+	for _, query := range queries {
+		parsed, err := services.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", query, err)
+		}
+		var expected []string
+		artists := catalog.Artists()
+		for i := range artists {
+			if parsed.Match(&artists[i], catalog) {
+				expected = append(expected, artists[i].Name)
+			}
+		}
+
+		results, err := svc.Search(query)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		got := make(map[string]bool)
+		for _, result := range results {
+			got[result.Artist.Name] = true
+		}
+		if len(got) != len(expected) {
+			t.Errorf("Search(%q): expected %v, got %d results", query, expected, len(got))
+			continue
+		}
+		for _, name := range expected {
+			if !got[name] {
+				t.Errorf("Search(%q): missing %s", query, name)
+			}
+		}
+	}
+}
+
+func TestSearchIndexRebuiltOnRefresh(t *testing.T) {
+	data := loadFixture(t)
+	source := api.NewMemorySource(data)
+	client := api.NewClient(source)
+	svc := services.New(client)
+
+	if suggestions, _ := svc.GetSuggestions("velvet"); len(suggestions) != 0 {
+		t.Fatalf("Expected no suggestions before the refresh, got %v", suggestions)
+	}
+	before, err := client.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := *data
+	next.Artists = append(append([]api.Artist(nil), data.Artists...), api.Artist{
+		ID: 99, Name: "Velvet Static", Members: []string{"Ada Lovelace"}, CreationDate: 2015, FirstAlbum: "01-01-2016",
+	})
+	source.Data = &next
+	if err := client.RefreshContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	suggestions, err := svc.GetSuggestions("velvet")
+	if err != nil || len(suggestions) != 1 || suggestions[0].Text != "Velvet Static" {
+		t.Errorf("Expected the new artist to be suggested, got %v (%v)", suggestions, err)
+	}
+	if got := searchNames(t, svc, "lovelace"); got != "Velvet Static" {
+		t.Errorf("Expected the new member to be searchable, got [%s]", got)
+	}
+	if len(before.Index().Search("velvet")) != 0 {
+		t.Error("Expected the previous snapshot's index to stay unchanged")
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"groupie-tracker/internal/api"
+	"groupie-tracker/internal/services"
+	"groupie-tracker/internal/textnorm"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Beyoncé", "beyonce"},
+		{"BEYONCÉ", "beyonce"},
+		{"Beyonce\u0301", "beyonce"},
+		{"São Paulo, Brazil", "sao paulo brazil"},
+		{"sao_paulo-brazil", "sao paulo brazil"},
+		{"Guns N' Roses", "guns n roses"},
+		{"  Motörhead!! ", "motorhead"},
+		{"Straße", "strasse"},
+		{"Sigur Rós & Björk", "sigur ros bjork"},
+		{"AC/DC", "ac dc"},
+		{"14-12-1973", "14 12 1973"},
+		{"08.2019", "08 2019"},
+		{"Earth, Wind & Fire", "earth wind fire"},
+		{"Кино", "кино"},
+	}
+	for _, tt := range tests {
+		if got := textnorm.Fold(tt.input); got != tt.expected {
+			t.Errorf("Fold(%q): expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	value := "Zoë Straße"
+	folded, offsets := textnorm.FoldOffsets(value)
+	if folded != "zoe strasse" || len(offsets) != len(folded) {
+		t.Fatalf("Unexpected fold %q with %d offsets", folded, len(offsets))
+	}
+	i := strings.Index(folded, "sse")
+	if got := value[offsets[i].Start:offsets[i+2].End]; got != "ße" {
+		t.Errorf("Expected offsets to map back to %q, got %q", "ße", got)
+	}
+}
+
+func newAccentedService(t *testing.T) *services.Service {
+	data := loadFixture(t)
+	next := *data
+	next.Artists = append(append([]api.Artist(nil), data.Artists...), api.Artist{
+		ID: 99, Name: "Beyoncé", Members: []string{"Beyoncé Knowles"}, CreationDate: 1997, FirstAlbum: "24-06-2003",
+	})
+	next.Relations.Index = append(append([]api.Relation(nil), data.Relations.Index...), api.Relation{
+		ID: 99, DatesLocations: map[string][]string{"zürich-switzerland": {"12-07-2023"}},
+	})
+	return services.New(api.NewClient(api.NewMemorySource(&next)))
+}
+
+func TestAccentInsensitiveSearch(t *testing.T) {
+	svc := newAccentedService(t)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"beyonce", "Beyoncé"},
+		{"BEYONCÉ", "Beyoncé"},
+		{"member:knowles", "Beyoncé"},
+		{"zurich", "Beyoncé"},
+		{"location:zürich", "Beyoncé"},
+		{"são paulo", "Guns N' Roses, Pink Floyd, SOJA"},
+		{"location:São_Paulo", "Guns N' Roses, Pink Floyd, SOJA"},
+		{"guns n roses", "Guns N' Roses"},
+		{`"meddows taylor"`, "Queen"},
+	}
+	for _, tt := range tests {
+		if got := searchNames(t, svc, tt.query); got != tt.expected {
+			t.Errorf("Query %q: expected [%s], got [%s]", tt.query, tt.expected, got)
+		}
+	}
+
+	results, err := svc.Search("beyonce")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected one result, got %v (%v)", results, err)
+	}
+	name := results[0].NameMatch()
+	if name == nil || len(name.Parts()) != 1 || !name.Parts()[0].Match || name.Parts()[0].Text != "Beyoncé" {
+		t.Errorf("Expected the whole accented name highlighted, got %+v", name)
+	}
+
+	suggestions, err := svc.GetSuggestions("São Pa")
+	if err != nil || len(suggestions) != 1 || suggestions[0].Text != "Sao Paulo, Brazil" {
+		t.Errorf("Unexpected suggestions %v (%v)", suggestions, err)
+	}
+	suggestions, err = svc.GetSuggestions("beyonc")
+	if err != nil || len(suggestions) != 2 || suggestions[0].Text != "Beyoncé" {
+		t.Errorf("Unexpected suggestions %v (%v)", suggestions, err)
+	}
+
+	filtered, err := svc.ApplyFilters(services.FilterParams{
+		CreationDateMax: 9999, FirstAlbumMax: 9999, MembersMax: 100,
+		Locations: []string{"São Paulo"},
+	})
+	if err != nil || len(filtered) != 3 {
+		t.Errorf("Expected three artists to have played in São Paulo, got %v (%v)", filtered, err)
+	}
+}